@@ -0,0 +1,130 @@
+package compoapp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// MustBind is like Bind but panics on error
+func MustBind[I, T any](c *Container) {
+	if err := Bind[I, T](c); err != nil {
+		panic(err)
+	}
+}
+
+// Bind declares that the interface I is satisfied by the provided type T,
+// e.g. Bind[IEmailService, *SMTPEmailService](container).
+//
+// Bound interfaces are injected with the instance of T even when other provided types implement I.
+func Bind[I, T any](c *Container) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.bind(key{typ: reflect.TypeFor[I]()}, key{typ: reflect.TypeFor[T]()})
+}
+
+// bind registers the implementation for the interface
+func (c *Container) bind(iface, impl key) error {
+	if iface.typ.Kind() != reflect.Interface {
+		return fmt.Errorf("cannot bind %s: not an interface", iface)
+	}
+	if !impl.typ.Implements(iface.typ) {
+		return fmt.Errorf("cannot bind %s to %s: it does not implement the interface", iface, impl)
+	}
+	if bound, exists := c.bindings[iface]; exists && bound != impl {
+		return fmt.Errorf("%s is already bound to %s", iface, bound)
+	}
+
+	c.debugf("%s bound to %s", iface, impl)
+	c.bindings[iface] = impl
+	return nil
+}
+
+// asBindings returns bindings requested with As: each interface is bound to the single result implementing it
+func asBindings(results []result, ifaces []any) (map[key]key, error) {
+	bindings := make(map[key]key, len(ifaces))
+	for _, iface := range ifaces {
+		ifaceType := reflect.TypeOf(iface)
+		if ifaceType == nil || ifaceType.Kind() != reflect.Pointer || ifaceType.Elem().Kind() != reflect.Interface {
+			return nil, fmt.Errorf("As expects pointers to interfaces, got %v", ifaceType)
+		}
+		ifaceType = ifaceType.Elem()
+
+		var impls []key
+		for _, r := range results {
+			if r.group == "" && r.key.typ.Implements(ifaceType) {
+				impls = append(impls, r.key)
+			}
+		}
+		if len(impls) != 1 {
+			return nil, fmt.Errorf("As(%s) must match exactly one result, matched %v", ifaceType, impls)
+		}
+		bindings[key{typ: ifaceType, name: impls[0].name}] = impls[0]
+	}
+	return bindings, nil
+}
+
+// resolveBindings provides bound interfaces by alias constructors of their implementations
+func (c *Container) resolveBindings() error {
+	var errs []error
+	for iface, impl := range c.bindings {
+		if ctor, exists := c.typesCtors[iface]; exists {
+			if ctor.alias && ctor.signature.args[0].key == impl {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%s is bound to %s but also provided by %s", iface, impl, ctor.name))
+			continue
+		}
+		if _, exists := c.typesCtors[impl]; !exists {
+			errs = append(errs, fmt.Errorf("%s is bound to %s, which is not provided", iface, impl))
+			continue
+		}
+		c.alias(iface, impl)
+	}
+	return errors.Join(errs...)
+}
+
+// implementationsOf finds implementations of the interface, preferring the primary one when several exist
+func (c *Container) implementationsOf(interfaceKey key) []key {
+	implementations := c.findImplementations(interfaceKey)
+	if len(implementations) < 2 {
+		return implementations
+	}
+
+	var primary []key
+	for _, k := range implementations {
+		if c.typesCtors[k].primary {
+			primary = append(primary, k)
+		}
+	}
+	if len(primary) == 1 {
+		c.debugf("%s is the primary implementation of %s", primary[0], interfaceKey)
+		return primary
+	}
+	return implementations
+}
+
+// alias registers a constructor providing the implementation as the interface
+func (c *Container) alias(iface, impl key) {
+	fnType := reflect.FuncOf([]reflect.Type{impl.typ}, []reflect.Type{iface.typ}, false)
+	fn := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		return []reflect.Value{args[0].Convert(iface.typ)}
+	})
+
+	c.debugf("%s is provided by %s", iface, impl)
+	cinfo := &constructorInfo{
+		fn:   fn.Interface(),
+		name: fnType.String(),
+		signature: fnSignature{
+			args:    []dependency{{key: impl, valueType: impl.typ}},
+			results: []result{{key: iface}},
+		},
+		lifetime:              c.typesCtors[impl].lifetime,
+		alias:                 true,
+		dependNeedsResolution: []bool{false},
+	}
+	c.constructors = append(c.constructors, cinfo)
+	c.typesCtors[iface] = cinfo
+	c.typeRegistry = append(c.typeRegistry, iface)
+}
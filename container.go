@@ -1,9 +1,14 @@
 package compoapp
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"reflect"
+	"runtime/debug"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -13,21 +18,35 @@ type Container struct {
 	// list of constructors
 	constructors []*constructorInfo
 	// Resolved instances
-	instances map[reflect.Type]any
+	instances map[key]any
 	// Registry of types. All types which returned from ctors
-	typeRegistry []reflect.Type
+	typeRegistry []key
 	// Lock for thread safety
 	mu sync.RWMutex
 	// Graph for dependency resolution
 	graph *dependencyGraph
 	// ctor for specific
-	typesCtors map[reflect.Type]*constructorInfo
+	typesCtors map[key]*constructorInfo
+	// value groups: group name -> member keys in registration order
+	groups map[string][]key
+	// decorators applied to instances of the key, in registration order
+	decorators map[key][]*decorator
+	// interface -> implementation declared with Bind or As
+	bindings map[key]key
+	// components initialized and started by a LifecycleRunner
+	launched map[any]bool
 
 	debug bool
 	// mark if container resolved
 	resolved bool
 	// Resolved types topsorted
 	sorted []any
+	// keys whose instances are listed in sorted
+	listed map[key]bool
+	// context of the running ResolveContext call, injected into constructors
+	ctx context.Context
+	// let constructor panics through instead of returning a PanicError
+	crashOnPanic bool
 }
 
 // Debug enables debug mode
@@ -38,6 +57,15 @@ func (c *Container) Debug() {
 	c.debug = true
 }
 
+// CrashOnPanic lets panics of constructors, decorators and invoked functions through.
+// By default they are recovered and returned as a PanicError.
+func (c *Container) CrashOnPanic() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.crashOnPanic = true
+}
+
 func (c *Container) debugf(format string, args ...any) {
 	if c.debug {
 		fmtStr := "[CONTAINER] " + format + "\n"
@@ -45,11 +73,72 @@ func (c *Container) debugf(format string, args ...any) {
 	}
 }
 
+// key identifies a node of the dependency graph: a type with an optional name
+type key struct {
+	typ  reflect.Type
+	name string
+	// value group the node belongs to, members are told apart by index
+	group string
+	index int
+}
+
+func (k key) String() string {
+	switch {
+	case k.group != "":
+		return fmt.Sprintf("%s[group=%q]#%d", k.typ, k.group, k.index)
+	case k.name != "":
+		return fmt.Sprintf("%s[name=%q]", k.typ, k.name)
+	default:
+		return k.typ.String()
+	}
+}
+
+// dependency describes a single constructor parameter or a field of an In parameter struct
+type dependency struct {
+	key key
+	// interface declared by the parameter, set once key is replaced with an implementation
+	iface key
+	// declared type of the parameter or field
+	valueType reflect.Type
+	// index of the constructor parameter
+	param int
+	// index of the field within the In parameter struct, nil for plain parameters
+	field []int
+	// group collects every member of the value group into a slice of key.typ
+	group string
+	// variadic parameters without a group collect the members of every value group of their element type
+	variadic bool
+	// context parameters receive the context of the resolution, see ResolveContext
+	isContext bool
+	// optional dependencies are injected as zero values when nothing provides them
+	optional bool
+	// wrapped optional dependencies are injected as Optional[T]
+	wrapped bool
+	// lazy dependencies are injected as Lazy[T] and built on first Get
+	lazy bool
+	// factory dependencies are injected as Provider[T] and built on every call, they are lazy too
+	factory bool
+	// breaksCycle leaves the lazy dependency out of sorting, so it may close a cycle
+	breaksCycle bool
+}
+
+// result describes a single value provided by a constructor: a result or a field of an Out result struct
+type result struct {
+	key key
+	// index of the constructor result
+	out int
+	// index of the field within the Out result struct, nil for plain results
+	field []int
+	// value group the result is added to
+	group string
+}
+
 // fnSignature - describes function args and return values
-// todo: for now we only support one return value
 type fnSignature struct {
-	args       []reflect.Type
-	returnType reflect.Type
+	args    []dependency
+	results []result
+	// the last constructor result is an error
+	hasError bool
 }
 
 // constructorInfo holds constructor function and metadata
@@ -57,42 +146,116 @@ type constructorInfo struct {
 	fn        any
 	name      string
 	signature fnSignature
+	// how long constructed instances live
+	lifetime Lifetime
+	// preferred implementation of interfaces, see Primary
+	primary bool
+	// alias constructors provide an implementation as the interface
+	alias bool
+	// Provide call site
+	location string
+	// generic function the constructor instantiates, see Template
+	template string
 	// New fields for interface resolution
 	dependNeedsResolution []bool // marks which dependencies need interface resolution
 }
 
+// String describes the constructor by the keys it provides
+func (ci *constructorInfo) String() string {
+	if len(ci.signature.results) == 0 {
+		return ci.name
+	}
+	if len(ci.signature.results) == 1 {
+		return ci.signature.results[0].key.String()
+	}
+	keys := make([]string, 0, len(ci.signature.results))
+	for _, r := range ci.signature.results {
+		keys = append(keys, r.key.String())
+	}
+	return "(" + strings.Join(keys, ", ") + ")"
+}
+
+// node returns the graph node of the constructor: the first key it provides
+func (ci *constructorInfo) node() key {
+	if len(ci.signature.results) == 0 {
+		return key{}
+	}
+	return ci.signature.results[0].key
+}
+
+// resultIndex returns position of the key among constructor results
+func (ci *constructorInfo) resultIndex(k key) int {
+	for i, r := range ci.signature.results {
+		if r.key == k {
+			return i
+		}
+	}
+	return -1
+}
+
+// edge is a dependency of one component on another
+type edge struct {
+	from, to key
+}
+
 // dependencyGraph represents the dependency relationships
 type dependencyGraph struct {
-	dependencies map[reflect.Type][]reflect.Type // component -> its dependencies
-	dependents   map[reflect.Type][]reflect.Type // component -> components that depend on it
+	dependencies map[key][]key // component -> its dependencies
+	dependents   map[key][]key // component -> components that depend on it
+	// edges of Lazy[T] and Provider[T] dependencies
+	lazy map[edge]bool
+	// edges of optional dependencies
+	optional map[edge]bool
+	// edges present only because of decorators of the dependent
+	decorated map[edge]bool
+	// edges left out of dependencies, so they do not take part in sorting:
+	// lazy edges breaking cycles and optional dependencies nothing provides
+	detached []edge
 }
 
 // NewContainer creates a new DI container
 func NewContainer() *Container {
 	return &Container{
 		constructors: []*constructorInfo{},
-		instances:    make(map[reflect.Type]any),
-		typeRegistry: []reflect.Type{},
-		typesCtors:   make(map[reflect.Type]*constructorInfo),
+		instances:    make(map[key]any),
+		typeRegistry: []key{},
+		typesCtors:   make(map[key]*constructorInfo),
+		groups:       make(map[string][]key),
+		decorators:   make(map[key][]*decorator),
+		bindings:     make(map[key]key),
+		launched:     make(map[any]bool),
 		graph: &dependencyGraph{
-			dependencies: make(map[reflect.Type][]reflect.Type),
-			dependents:   make(map[reflect.Type][]reflect.Type),
+			dependencies: make(map[key][]key),
+			dependents:   make(map[key][]key),
+			lazy:         make(map[edge]bool),
+			optional:     make(map[edge]bool),
+			decorated:    make(map[edge]bool),
 		},
 	}
 }
 
 // MustProvide registers a constructor function and panic on error
-func (c *Container) MustProvide(constructor any) {
-	if err := c.Provide(constructor); err != nil {
+func (c *Container) MustProvide(constructor any, opts ...ProvideOption) {
+	if err := c.Provide(constructor, opts...); err != nil {
 		panic(err)
 	}
 }
 
 // Provide registers a constructor function
-func (c *Container) Provide(constructor any) error {
+func (c *Container) Provide(constructor any, opts ...ProvideOption) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	return c.register(constructor, callerLocation(), "", opts...)
+}
+
+// register adds the constructor called at location, template is the generic function it instantiates if any
+func (c *Container) register(constructor any, location, template string, opts ...ProvideOption) error {
+	var options provideOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	constructorValue := reflect.ValueOf(constructor)
 	if constructorValue.Kind() != reflect.Func {
 		return fmt.Errorf("constructor must be a function")
@@ -105,40 +268,163 @@ func (c *Container) Provide(constructor any) error {
 	if err != nil {
 		return fmt.Errorf("failed to analyze constructor: %w", err)
 	}
+	if err := applyParamTags(signature.args, options.paramTags, constructorType.NumIn()); err != nil {
+		return fmt.Errorf("failed to analyze constructor: %w", err)
+	}
+	if options.name != "" && options.group != "" {
+		return fmt.Errorf("constructor cannot be both named and grouped")
+	}
 
 	// Initialize dependency resolution tracking
-	dependNeedsResolution := make([]bool, len(signature.args))
-	for i, arg := range signature.args {
-		// Mark interfaces for resolution
-		if arg.Kind() == reflect.Interface {
-			dependNeedsResolution[i] = true
+	dependNeedsResolution := interfaceDependencies(signature.args)
+
+	// Name and Group apply to results without their own Out field tags
+	provided := make(map[key]bool)
+	for i := range signature.results {
+		r := &signature.results[i]
+		if r.key.name == "" && r.group == "" {
+			r.key.name = options.name
+			r.group = options.group
+		}
+		if provided[r.key] {
+			return fmt.Errorf("constructor provides %s more than once", r.key)
+		}
+		provided[r.key] = true
+	}
+
+	// a type is provided once unless it is replaced on purpose with Override
+	for _, r := range signature.results {
+		if r.group == "" {
+			if err := c.checkDuplicate(r.key, options.override, location); err != nil {
+				return err
+			}
+		}
+	}
+
+	bindings, err := asBindings(signature.results, options.as)
+	if err != nil {
+		return fmt.Errorf("failed to analyze constructor: %w", err)
+	}
+	for iface, impl := range bindings {
+		if err := c.bind(iface, impl); err != nil {
+			return err
+		}
+	}
+
+	for i := range signature.results {
+		r := &signature.results[i]
+		if _, exists := c.typesCtors[r.key]; exists && r.group == "" {
+			c.debugf("%s is overridden", r.key)
+			c.unregister(r.key)
+		}
+		if r.group != "" {
+			r.key = key{typ: r.key.typ, group: r.group, index: len(c.groups[r.group])}
+			c.groups[r.group] = append(c.groups[r.group], r.key)
 		}
 	}
 
 	// Store constructor info
 	cinfo := &constructorInfo{
 		fn:                    constructor,
-		name:                  constructorType.String(),
+		name:                  functionName(constructor),
 		signature:             signature,
+		lifetime:              options.lifetime,
+		primary:               options.primary,
 		dependNeedsResolution: dependNeedsResolution,
+		location:              location,
+		template:              template,
 	}
 	c.constructors = append(c.constructors, cinfo)
-	// todo: only one return value available right now
-	c.typesCtors[signature.returnType] = cinfo
 
-	// Register return types in type registry for interface resolution
-	returnType := signature.returnType
-	c.typeRegistry = append(c.typeRegistry, returnType)
-	// todo: somehow we should find out that we have pointer, reference and values
+	for _, r := range signature.results {
+		c.typesCtors[r.key] = cinfo
+
+		// Register return types in type registry for interface resolution
+		c.typeRegistry = append(c.typeRegistry, r.key)
+	}
 
 	return nil
 }
 
+// checkDuplicate rejects a second constructor for the key, unless it overrides the first one
+func (c *Container) checkDuplicate(k key, override bool, location string) error {
+	existing, exists := c.typesCtors[k]
+	if !exists {
+		return nil
+	}
+	if _, built := c.instances[k]; built {
+		return fmt.Errorf("cannot provide %s again at %s: it is already constructed", k, location)
+	}
+	// aliases of bound and decorated interfaces give way to direct constructors
+	if override || existing.alias {
+		return nil
+	}
+	return fmt.Errorf("%s is already provided by %s%s, provided again at %s, use Override to replace it",
+		k, existing.name, atLocation(existing.location), location)
+}
+
+// unregister removes the key from the registry, a constructor left without keys is removed too
+func (c *Container) unregister(k key) {
+	old := c.typesCtors[k]
+	delete(c.typesCtors, k)
+	c.typeRegistry = slices.DeleteFunc(c.typeRegistry, func(r key) bool { return r == k })
+
+	for _, r := range old.signature.results {
+		if c.typesCtors[r.key] == old {
+			return
+		}
+	}
+	c.constructors = slices.DeleteFunc(c.constructors, func(ci *constructorInfo) bool { return ci == old })
+}
+
 // analyzeFunction extracts dependencies and return types from function signature
 func (c *Container) analyzeFunction(fnType reflect.Type) (fnSignature, error) {
 	c.debugf("analyzing constructor %s signature", fnType.String())
 
-	args := make([]reflect.Type, 0, fnType.NumIn())
+	args, err := c.analyzeParams(fnType)
+	if err != nil {
+		return fnSignature{}, err
+	}
+
+	// Analyze return values
+	// Support (T1, ..., Tn) with an optional trailing error, any type implementing error counts,
+	// Out structs are expanded to their fields
+	numOut := fnType.NumOut()
+	hasError := numOut > 1 && fnType.Out(numOut-1).Implements(errorType)
+	if hasError {
+		numOut--
+	}
+
+	results := make([]result, 0, numOut)
+	for i := 0; i < numOut; i++ {
+		resultType := fnType.Out(i)
+		c.debugf("result: %d, type: %s", i, resultType.String())
+
+		if isOut(resultType) {
+			fields, err := outFields(resultType, i)
+			if err != nil {
+				return fnSignature{}, err
+			}
+			results = append(results, fields...)
+			continue
+		}
+
+		if err := checkResultType(resultType); err != nil {
+			return fnSignature{}, err
+		}
+		results = append(results, result{key: key{typ: resultType}, out: i})
+	}
+
+	if len(results) == 0 {
+		return fnSignature{}, fmt.Errorf("constructor must return (T) or (T, error)")
+	}
+
+	return fnSignature{args: args, results: results, hasError: hasError}, nil
+}
+
+// analyzeParams extracts dependencies from function parameters
+func (c *Container) analyzeParams(fnType reflect.Type) ([]dependency, error) {
+	args := make([]dependency, 0, fnType.NumIn())
 
 	// Analyze args (dependencies)
 	for i := 0; i < fnType.NumIn(); i++ {
@@ -146,36 +432,106 @@ func (c *Container) analyzeFunction(fnType reflect.Type) (fnSignature, error) {
 		// Generate dependency name from parameter type
 		c.debugf("arg: %d, type: %s", i, paramType.String())
 
-		args = append(args, paramType)
+		if isIn(paramType) {
+			fields, err := inFields(paramType, i)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, fields...)
+			continue
+		}
+
+		dep := newDependency(paramType, i)
+		dep.variadic = fnType.IsVariadic() && i == fnType.NumIn()-1
+		args = append(args, dep)
 	}
 
-	// Analyze return values
-	// Support either: (*T) or (*T, error)
-	if fnType.NumOut() == 0 || fnType.NumOut() > 2 {
-		return fnSignature{}, fmt.Errorf("constructor must return (*T) or (*T, error)")
+	return args, nil
+}
+
+// interfaceDependencies marks dependencies which need interface resolution
+func interfaceDependencies(args []dependency) []bool {
+	dependNeedsResolution := make([]bool, len(args))
+	for i, arg := range args {
+		if !arg.grouped() && !arg.isContext && arg.key.typ.Kind() == reflect.Interface {
+			dependNeedsResolution[i] = true
+		}
+	}
+	return dependNeedsResolution
+}
+
+// checkResultType checks that values of the type can be provided.
+// Values of any kind are accepted, except the types the container handles itself.
+func checkResultType(resultType reflect.Type) error {
+	_, optional := optionalElem(resultType)
+	_, lazy := lazyElem(resultType)
+	_, provider := providerElem(resultType)
+	switch {
+	case resultType == contextType:
+		return fmt.Errorf("%s is passed by ResolveContext and cannot be provided", resultType)
+	case resultType == errorType:
+		return fmt.Errorf("constructor cannot provide error values, an error must be the last result")
+	case isIn(resultType), isOut(resultType):
+		return fmt.Errorf("%s is a parameter or result struct and cannot be provided", resultType)
+	case optional, lazy, provider:
+		return fmt.Errorf("%s is built by the container and cannot be provided", resultType)
 	}
+	return nil
+}
 
-	firstOut := fnType.Out(0)
-	if firstOut.Kind() != reflect.Pointer && firstOut.Kind() != reflect.Interface {
-		return fnSignature{}, fmt.Errorf("constructor must return pointer or interface value as first result")
+// counterpart returns the key of the pointer to the type, or of the pointed type for pointers.
+// T and *T are distinct types, the counterpart is only suggested when a request is not provided.
+func (c *Container) counterpart(k key) string {
+	alt := k
+	if k.typ.Kind() == reflect.Pointer {
+		alt.typ = k.typ.Elem()
+	} else {
+		alt.typ = reflect.PointerTo(k.typ)
 	}
+	if _, exists := c.typesCtors[alt]; !exists {
+		return ""
+	}
+	return alt.String()
+}
 
-	if fnType.NumOut() == 2 {
-		secondOut := fnType.Out(1)
-		errorType := reflect.TypeOf((*error)(nil)).Elem()
-		if !secondOut.Implements(errorType) {
-			return fnSignature{}, fmt.Errorf("second return value must be error")
-		}
+// grouped reports whether the dependency collects value group members
+func (dep dependency) grouped() bool {
+	return dep.group != "" || dep.variadic
+}
+
+// newDependency describes a parameter or field of the given type, unwrapping Optional[T], Lazy[T] and Provider[T]
+func newDependency(valueType reflect.Type, param int) dependency {
+	dep := dependency{key: key{typ: valueType}, valueType: valueType, param: param}
+	dep.isContext = valueType == contextType
+
+	if elemType, ok := optionalElem(valueType); ok {
+		dep.key.typ = elemType
+		dep.optional = true
+		dep.wrapped = true
+	} else if elemType, ok := lazyElem(valueType); ok {
+		dep.key.typ = elemType
+		dep.lazy = true
+	} else if elemType, ok := providerElem(valueType); ok {
+		dep.key.typ = elemType
+		dep.lazy = true
+		dep.factory = true
 	}
 
-	return fnSignature{args, firstOut}, nil
+	return dep
 }
 
 // Resolve resolves and returns an instance of the requested type.
 // Target must be a pointer to a pointer.
-func (c *Container) Resolve(target any) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+func (c *Container) Resolve(target any, opts ...ResolveOption) error {
+	return c.ResolveContext(context.Background(), target, opts...)
+}
+
+// resolve builds singletons and sets the target. Scoped instances are taken from scope, which is nil for the root container.
+func (c *Container) resolve(scope *Scope, target any, opts ...ResolveOption) (err error) {
+	var options resolveOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 
 	targetValue := reflect.ValueOf(target)
 	if targetValue.Kind() != reflect.Pointer || targetValue.IsNil() {
@@ -183,115 +539,266 @@ func (c *Container) Resolve(target any) error {
 	}
 
 	targetType := targetValue.Type().Elem()
+	targetKey := key{typ: targetType, name: options.name}
+	defer func() {
+		if err != nil {
+			c.tracePaths(err, targetKey)
+		}
+	}()
 
-	// Step 1: Resolve interfaces to implementations
-	if err := c.resolveInterfaces(); err != nil {
-		return fmt.Errorf("interface resolution failed: %w", err)
+	// Steps 1-2: Resolve interfaces, build dependency graph and sort.
+	// Types the target does not reach are checked only with Eager
+	var checked []key
+	if !options.eager {
+		checked = []key{targetKey}
 	}
-
-	c.rebuildGraph()
-
-	if err := c.validateDependencies(); err != nil {
+	sortedTypes, err := c.prepare(checked)
+	if err != nil {
 		return err
 	}
 
-	// Step 2: Build dependency graph and sort
-	sortedTypes, err := c.topologicalSort()
-	if err != nil {
-		return fmt.Errorf("dependency resolution failed: %w", err)
+	if _, exists := c.typesCtors[targetKey]; !exists {
+		return &MissingDependencyError{Dependency: targetKey.String(), missing: targetKey, counterpart: c.counterpart(targetKey)}
 	}
 
-	// Step 3: Resolve all dependencies in order
-	sorted := make([]any, 0, len(sortedTypes))
+	// Step 3: Resolve singletons the target needs in order, every type with Eager.
+	// Scoped, transient and lazy types are built on demand
+	roots := []key{targetKey}
+	if options.eager {
+		roots = c.rootKeys()
+	}
+	eager := c.eagerKeys(roots)
+	var pending []key
+	// constructors with several results are called once
+	queued := make(map[*constructorInfo]bool)
 	for _, name := range sortedTypes {
-		// todo: here might be tagged instances too
-		if err := c.resolveInstance(name); err != nil {
-			return fmt.Errorf("failed to resolve %s: %w", name, err)
-		}
-		if v, ok := c.instances[name]; ok {
-			// todo: in case this
-			sorted = append(sorted, v)
-		} else {
-			return fmt.Errorf("this is impossible, but this happens: we can't find the instance for registered type")
+		ctor := c.typesCtors[name]
+		if ctor.lifetime != Singleton {
+			continue
+		}
+		if !eager[name] {
+			c.debugf("%s is not needed yet, deferring", name)
+			continue
+		}
+		// instances built by earlier calls are reused
+		if _, exists := c.instances[name]; !exists && !queued[ctor] {
+			queued[ctor] = true
+			pending = append(pending, name)
 		}
 	}
-	c.sorted = sorted
+	if err := c.build(pending, options.workers); err != nil {
+		return err
+	}
+
+	c.sorted, c.listed = nil, nil
+	if err := c.appendSorted(sortedTypes, eager); err != nil {
+		return err
+	}
 
 	// Step 4: Set the target value
-	if instance, exists := c.instances[targetType]; exists {
-		instanceValue := reflect.ValueOf(instance)
-		if instanceValue.Type().AssignableTo(targetType) {
-			targetValue.Elem().Set(instanceValue)
+	instance, err := c.instanceFor(targetKey, scope)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", targetKey, err)
+	}
 
-			c.resolved = true
-			return nil
-		}
-		return fmt.Errorf("resolved instance type %s is not assignable to target type %s",
-			instanceValue.Type(), targetType)
+	instanceValue := reflect.ValueOf(instance)
+	if instanceValue.Type().AssignableTo(targetType) {
+		targetValue.Elem().Set(instanceValue)
+
+		c.resolved = true
+		return nil
+	}
+	return fmt.Errorf("resolved instance type %s is not assignable to target type %s",
+		instanceValue.Type(), targetType)
+}
+
+// prepare resolves interfaces, rebuilds and validates the dependency graph and returns its nodes in construction order.
+// Only the types the roots reach are resolved, validated and sorted, so unrelated registrations cannot fail
+// the call; nil roots prepare every type.
+func (c *Container) prepare(roots []key) ([]key, error) {
+	// Step 1: Resolve interfaces to implementations
+	nodes, err := c.resolveInterfaces(roots)
+	if err != nil {
+		// the graph of declared dependencies is enough to trace paths
+		c.rebuildGraph()
+		return nil, fmt.Errorf("interface resolution failed: %w", err)
+	}
+
+	c.rebuildGraph()
+
+	if err := c.validateDependencies(nodes); err != nil {
+		return nil, err
+	}
+
+	// Step 2: Build dependency graph and sort
+	sortedTypes, err := c.topologicalSort(nodes)
+	if err != nil {
+		return nil, fmt.Errorf("dependency resolution failed: %w", err)
 	}
 
-	return fmt.Errorf("no instance found for type %s", targetType)
+	if err := c.validateLifetimes(nodes); err != nil {
+		return nil, err
+	}
+	return sortedTypes, nil
 }
 
-// resolveInterfaces resolves interface dependencies to concrete implementations
-func (c *Container) resolveInterfaces() error {
+// resolveInterfaces resolves interface dependencies to concrete implementations.
+// With roots only the constructors they reach are resolved and the reached types are returned, nil means every type.
+func (c *Container) resolveInterfaces(roots []key) (map[key]bool, error) {
 	c.debugf("resolving interfaces")
+	errs := []error{c.resolveBindings(), c.resolveDecorated()}
+	if roots != nil {
+		nodes, err := c.reachable(roots)
+		return nodes, errors.Join(append(errs, err)...)
+	}
+
 	// For each constructor, check if it has interface dependencies that need resolution
-	for _, ctorInfo := range c.constructors {
-		for i, needsResolution := range ctorInfo.dependNeedsResolution {
-			if !needsResolution {
-				continue
+	for _, ctorInfo := range c.signatures() {
+		errs = append(errs, c.resolveDependencies(ctorInfo))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// reachable resolves the constructors and decorators of the types the roots reach, lazily or not,
+// and returns these types. An interface without a direct constructor reaches its implementations.
+func (c *Container) reachable(roots []key) (map[key]bool, error) {
+	nodes := make(map[key]bool)
+	all := func(dependency) bool { return false }
+	var errs []error
+
+	var visit func(k key)
+	visit = func(k key) {
+		if nodes[k] {
+			return
+		}
+		nodes[k] = true
+
+		ctor, exists := c.typesCtors[k]
+		if !exists {
+			if k.typ.Kind() == reflect.Interface {
+				for _, impl := range c.implementationsOf(k) {
+					visit(impl)
+				}
 			}
+			return
+		}
+		errs = append(errs, c.resolveDependencies(ctor))
+		for _, d := range c.decorators[k] {
+			errs = append(errs, c.resolveDependencies(d.constructorInfo))
+		}
+		for _, dep := range append(c.dependencyKeys(ctor, all), c.decoratorKeys(k, all)...) {
+			visit(dep)
+		}
+	}
+	for _, k := range roots {
+		visit(k)
+	}
+	return nodes, errors.Join(errs...)
+}
 
-			signature := &ctorInfo.signature
+// resolveDependencies replaces interface dependencies of the constructor with their implementations
+func (c *Container) resolveDependencies(ctorInfo *constructorInfo) error {
+	// a built singleton keeps the implementations it received, types provided later do not change them
+	if c.built(ctorInfo) {
+		return nil
+	}
 
-			interfaceType := signature.args[i]
+	var errs []error
+	for i, needsResolution := range ctorInfo.dependNeedsResolution {
+		if !needsResolution {
+			continue
+		}
 
-			// If there's already a constructor that directly returns this interface type, skip resolution
-			if _, exists := c.typesCtors[interfaceType]; exists {
-				c.debugf("interface %s has a direct constructor, skipping resolution", interfaceType)
-				continue
-			}
+		dep := &ctorInfo.signature.args[i]
+		// the declared interface is kept, so resolution is repeated after new registrations
+		if dep.iface.typ == nil {
+			dep.iface = dep.key
+		}
+		interfaceKey := dep.iface
+		dep.key = interfaceKey
 
-			// Find implementation
-			implementations := c.findImplementations(interfaceType)
-			if len(implementations) == 0 {
-				return fmt.Errorf("no implementation found for interface %s", interfaceType.String())
-			}
-			if len(implementations) > 1 {
-				return fmt.Errorf("multiple implementations found for interface %s: %v",
-					interfaceType.String(), implementations)
-			}
+		// If there's already a constructor that directly returns this interface type, skip resolution
+		if _, exists := c.typesCtors[interfaceKey]; exists {
+			c.debugf("interface %s has a direct constructor, skipping resolution", interfaceKey)
+			continue
+		}
 
-			// Replace interface dependency with concrete implementation
-			c.debugf("%s replaced with implementation %s", signature.args[i], implementations[0])
-			signature.args[i] = implementations[0]
+		// Find implementation
+		implementations := c.implementationsOf(interfaceKey)
+		if len(implementations) == 0 && dep.optional {
+			c.debugf("no implementation found for optional interface %s", interfaceKey)
+			continue
 		}
+		if len(implementations) == 0 {
+			errs = append(errs, &MissingDependencyError{
+				Dependency:  interfaceKey.String(),
+				Constructor: ctorInfo.name,
+				Location:    ctorInfo.location,
+				node:        ctorInfo.node(),
+				missing:     interfaceKey,
+				iface:       true,
+			})
+			continue
+		}
+		if len(implementations) > 1 {
+			errs = append(errs, c.ambiguousBinding(ctorInfo, interfaceKey, implementations))
+			continue
+		}
+
+		// Replace interface dependency with concrete implementation
+		c.debugf("%s replaced with implementation %s", interfaceKey, implementations[0])
+		dep.key = implementations[0]
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
-// findImplementations finds concrete implementations for an interface type
-func (c *Container) findImplementations(interfaceType reflect.Type) []reflect.Type {
-	c.debugf("searching implementation for %s", interfaceType)
-	var implementations []reflect.Type
+// built reports whether the singleton constructor or decorator has already been called
+func (c *Container) built(ctor *constructorInfo) bool {
+	if ctor.lifetime != Singleton {
+		return false
+	}
+	for _, r := range ctor.signature.results {
+		if _, exists := c.instances[r.key]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// ambiguousBinding describes the interface dependency of the constructor several types implement
+func (c *Container) ambiguousBinding(ctor *constructorInfo, interfaceKey key, implementations []key) error {
+	impls := make([]string, 0, len(implementations))
+	for _, k := range implementations {
+		impls = append(impls, k.String())
+	}
+	return &AmbiguousBindingError{
+		Interface:       interfaceKey.String(),
+		Implementations: impls,
+		Constructor:     ctor.name,
+		Location:        ctor.location,
+		node:            ctor.node(),
+		iface:           interfaceKey,
+	}
+}
+
+// findImplementations finds concrete implementations for an interface type.
+// Only implementations registered under the same name are considered, group members are skipped.
+func (c *Container) findImplementations(interfaceKey key) []key {
+	c.debugf("searching implementation for %s", interfaceKey)
+	interfaceType := interfaceKey.typ
+	var implementations []key
 
 	// For interface types, look for concrete implementations
-	for _, typ := range c.typeRegistry {
+	for _, k := range c.typeRegistry {
+		typ := k.typ
 		// todo: may be just return error at the Provide stage?
-		if typ.Kind() == reflect.Interface {
+		if typ.Kind() == reflect.Interface || k.name != interfaceKey.name || k.group != "" {
 			continue
 		}
 		c.debugf("checking %s", typ)
-		// Check direct implementation
+		// values are injected as provided, so methods of *T do not count for a provided T
 		if typ.Implements(interfaceType) {
-			implementations = append(implementations, typ)
-			c.debugf("%s implements %s", typ, interfaceType)
-			continue
-		}
-		// Check pointer implementation
-		if reflect.PointerTo(typ).Implements(interfaceType) {
-			implementations = append(implementations, typ)
+			implementations = append(implementations, k)
 			c.debugf("%s implements %s", typ, interfaceType)
 		}
 	}
@@ -301,19 +808,26 @@ func (c *Container) findImplementations(interfaceType reflect.Type) []reflect.Ty
 }
 
 // topologicalSort performs topological sort on dependency graph
-func (c *Container) topologicalSort() ([]reflect.Type, error) {
+func (c *Container) topologicalSort(nodes map[key]bool) ([]key, error) {
 	// Kahn's algorithm for topological sorting
 	c.debugf("started topological sort")
-	inDegree := make(map[reflect.Type]int)
+	inDegree := make(map[key]int)
+	// nil nodes sort every type
+	sorted := func(k key) bool { return nodes == nil || nodes[k] }
 
 	// Initialize in-degrees
 	for _, typ := range c.typeRegistry {
-		inDegree[typ] = 0
+		if sorted(typ) {
+			inDegree[typ] = 0
+		}
 	}
 	c.debugf("initialized in-degrees: %v", inDegree)
 
 	// Calculate in-degrees
 	for typ := range c.graph.dependencies {
+		if !sorted(typ) {
+			continue
+		}
 		deps := c.graph.dependencies[typ]
 		inDegree[typ] = len(deps) // Set the actual number of dependencies
 		c.debugf("type %s has %d dependencies: %v", typ, len(deps), deps)
@@ -321,8 +835,8 @@ func (c *Container) topologicalSort() ([]reflect.Type, error) {
 	c.debugf("calculated in-degrees: %v", inDegree)
 
 	// Find nodes with zero in-degree
-	queue := []reflect.Type{}
-	result := []reflect.Type{}
+	queue := []key{}
+	result := []key{}
 
 	for typ, degree := range inDegree {
 		if degree == 0 {
@@ -330,6 +844,8 @@ func (c *Container) topologicalSort() ([]reflect.Type, error) {
 			c.debugf("added to queue (zero in-degree): %s", typ)
 		}
 	}
+	// keys are queued in a stable order, so the order of construction does not change between runs
+	slices.SortFunc(queue, compareKeys)
 	c.debugf("initial queue: %v", queue)
 
 	// Process nodes
@@ -340,83 +856,367 @@ func (c *Container) topologicalSort() ([]reflect.Type, error) {
 		c.debugf("processing: %s", current)
 
 		// Reduce in-degree for dependents
+		var ready []key
 		for _, dependent := range c.graph.dependents[current] {
+			if !sorted(dependent) {
+				continue
+			}
 			inDegree[dependent]--
 			c.debugf("reduced in-degree for %s: %d", dependent, inDegree[dependent])
 			if inDegree[dependent] == 0 {
-				queue = append(queue, dependent)
+				ready = append(ready, dependent)
 				c.debugf("added to queue: %s", dependent)
 			}
 		}
+		slices.SortFunc(ready, compareKeys)
+		queue = append(queue, ready...)
 	}
 	c.debugf("final result: %v", result)
 
 	// Check for circular dependencies
 	// Note: This should be equal to the number of types that have constructors
 	typesWithConstructors := 0
-	for range c.typesCtors {
-		typesWithConstructors++
+	for k := range c.typesCtors {
+		if sorted(k) {
+			typesWithConstructors++
+		}
 	}
 
 	if len(result) != typesWithConstructors {
-		return nil, fmt.Errorf("circular dependency detected: processed %d out of %d types", len(result), typesWithConstructors)
+		c.debugf("circular dependency detected: processed %d out of %d types", len(result), typesWithConstructors)
+		remaining := make(map[key]bool)
+		for typ, degree := range inDegree {
+			if degree > 0 {
+				remaining[typ] = true
+			}
+		}
+		cycles := c.findCycles(remaining)
+		if len(cycles) == 0 {
+			return nil, fmt.Errorf("circular dependency detected: processed %d out of %d types", len(result), typesWithConstructors)
+		}
+		return nil, &CycleError{Cycles: cycles, node: cycles[0].keys[0]}
 	}
 
 	return result, nil
 }
 
-// resolveInstance creates an instance for a given type
-func (c *Container) resolveInstance(typ reflect.Type) error {
-	// check if we even have such type returned from ctors
-	if _, exists := c.typesCtors[typ]; !exists {
-		return fmt.Errorf("no constructor registered for %s", typ)
+// resolveInstance creates instances for all results of a singleton constructor
+func (c *Container) resolveInstance(ctor *constructorInfo) error {
+	instances, err := c.construct(ctor, nil)
+	if err != nil {
+		return err
 	}
 
-	// find specific ctor which returns desired type
-	// above we check that we have registered constructor, so no worries
-	ctor := c.typesCtors[typ]
+	c.storeInstances(ctor, instances)
+	return nil
+}
 
-	constructorValue := reflect.ValueOf(ctor.fn)
-	constructorType := constructorValue.Type()
+// storeInstances keeps the singleton instances of the constructor
+func (c *Container) storeInstances(ctor *constructorInfo, instances []any) {
+	for i, r := range ctor.signature.results {
+		// results overridden by another constructor are dropped
+		if c.typesCtors[r.key] == ctor {
+			c.instances[r.key] = instances[i]
+		}
+	}
+}
+
+// construct calls the constructor with its dependencies and returns its results in signature order.
+// Results are decorated before they are returned.
+func (c *Container) construct(ctor *constructorInfo, scope *Scope) ([]any, error) {
+	args, err := c.constructorArgs(ctor, scope, -1, reflect.Value{})
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := c.call(ctor, args)
+	if err != nil {
+		return nil, constructorError(ctor, ctor.node(), err)
+	}
+	return c.decorateResults(ctor, results, scope)
+}
 
-	// Prepare arguments
+// decorateResults extracts the provided values from the constructor results and decorates them
+func (c *Container) decorateResults(ctor *constructorInfo, results []reflect.Value, scope *Scope) ([]any, error) {
+	var err error
+	instances := make([]any, len(ctor.signature.results))
+	for i, r := range ctor.signature.results {
+		value := results[r.out]
+		if r.field != nil {
+			value = value.FieldByIndex(r.field)
+		}
+
+		instances[i], err = c.decorate(r.key, value.Interface(), scope)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return instances, nil
+}
+
+// constructorArgs prepares constructor arguments. The dependency at index decorated, if any, receives the given value.
+func (c *Container) constructorArgs(ctor *constructorInfo, scope *Scope, decorated int, decoratedValue reflect.Value) ([]reflect.Value, error) {
+	constructorType := reflect.TypeOf(ctor.fn)
 	args := make([]reflect.Value, constructorType.NumIn())
 
-	for i := 0; i < constructorType.NumIn(); i++ {
-		depType := ctor.signature.args[i] // Use resolved dependency name
+	for i, dep := range ctor.signature.args {
+		value := decoratedValue
+		if i != decorated {
+			var err error
+			if value, err = c.dependencyValue(dep, ctor, scope); err != nil {
+				return nil, err
+			}
+		}
 
-		// Get dependency instance
-		depInstance, depExists := c.instances[depType]
-		if !depExists {
-			return fmt.Errorf("dependency %s not resolved for %s", depType, typ)
+		if dep.field == nil {
+			args[dep.param] = value
+			continue
+		}
+		if !args[dep.param].IsValid() {
+			args[dep.param] = reflect.New(constructorType.In(dep.param)).Elem()
 		}
+		args[dep.param].FieldByIndex(dep.field).Set(value)
+	}
 
-		args[i] = reflect.ValueOf(depInstance)
+	// In parameter structs without fields to inject
+	for i := range args {
+		if !args[i].IsValid() {
+			args[i] = reflect.Zero(constructorType.In(i))
+		}
 	}
 
-	// Call constructor
-	results := constructorValue.Call(args)
+	return args, nil
+}
+
+// call calls the constructor and returns its results, the trailing error is returned separately
+func (c *Container) call(ctor *constructorInfo, args []reflect.Value) ([]reflect.Value, error) {
+	c.debugf("calling constructor %s", ctor.name)
+	return callIn(c.resolutionContext(), c.crashOnPanic, ctor, args)
+}
+
+// callIn is call with the context and the panic mode given, it does not touch the container
+// and may run without the lock
+func callIn(ctx context.Context, crashOnPanic bool, ctor *constructorInfo, args []reflect.Value) ([]reflect.Value, error) {
+	// Call constructor, the variadic parameter receives its slice as is
+	fn := reflect.ValueOf(ctor.fn)
+	results, err := callContext(ctx, func() (results []reflect.Value, err error) {
+		if !crashOnPanic {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Constructor: ctor.name, Location: ctor.location, Value: r, Stack: debug.Stack(), node: ctor.node()}
+				}
+			}()
+		}
+		if fn.Type().IsVariadic() {
+			return fn.CallSlice(args), nil
+		}
+		return fn.Call(args), nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Handle optional error return (when present and non-nil)
-	if len(results) > 1 {
+	if ctor.signature.hasError {
 		lastResult := results[len(results)-1]
-		errorType := reflect.TypeOf((*error)(nil)).Elem()
-		if lastResult.Type().Implements(errorType) && !lastResult.IsNil() {
-			return lastResult.Interface().(error)
+		// the zero value of a custom error type, e.g. a nil *MyErr, is no error
+		if !lastResult.IsZero() {
+			return nil, lastResult.Interface().(error)
 		}
 	}
 
-	// Store first return value as instance
-	if len(results) > 0 {
-		c.instances[typ] = results[0].Interface()
-	}
+	return results, nil
+}
 
+// appendSorted lists the singletons of eager not listed yet, in sorted order, however they were built
+func (c *Container) appendSorted(sortedTypes []key, eager map[key]bool) error {
+	if c.listed == nil {
+		c.listed = make(map[key]bool)
+	}
+	var sorted []any
+	for _, name := range sortedTypes {
+		if !eager[name] || c.listed[name] || c.typesCtors[name].lifetime != Singleton {
+			continue
+		}
+		v, ok := c.instances[name]
+		if !ok {
+			return fmt.Errorf("this is impossible, but this happens: we can't find the instance for registered type")
+		}
+		c.listed[name] = true
+		sorted = append(sorted, v)
+	}
+	c.sorted = uniqueInstances(append(c.sorted, sorted...))
 	return nil
 }
 
+// uniqueInstances drops repeated instances, e.g. a client provided together with its interface view
+func uniqueInstances(instances []any) []any {
+	seen := make(map[any]bool)
+	unique := make([]any, 0, len(instances))
+	for _, instance := range instances {
+		if instance != nil && reflect.ValueOf(instance).Comparable() {
+			if seen[instance] {
+				continue
+			}
+			seen[instance] = true
+		}
+		unique = append(unique, instance)
+	}
+	return unique
+}
+
+// dependencyValue returns the value injected for the dependency of the constructor
+func (c *Container) dependencyValue(dep dependency, ctor *constructorInfo, scope *Scope) (reflect.Value, error) {
+	if dep.grouped() {
+		return c.groupValue(dep, scope)
+	}
+	if dep.isContext {
+		return reflect.ValueOf(c.resolutionContext()), nil
+	}
+
+	if _, exists := c.typesCtors[dep.key]; !exists && dep.optional {
+		c.debugf("optional dependency %s of %s is not provided, injecting zero value", dep.key, ctor)
+		return reflect.Zero(dep.valueType), nil
+	}
+
+	if dep.factory {
+		return c.providerValue(dep.valueType, dep, scope), nil
+	}
+	if dep.lazy {
+		return c.lazyValue(dep.valueType, dep, ctor, scope), nil
+	}
+
+	// Get dependency instance
+	depInstance, err := c.instanceFor(dep.key, scope)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("dependency %s not resolved for %s: %w", dep.key, ctor, err)
+	}
+	if dep.wrapped {
+		return optionalValue(dep.valueType, depInstance), nil
+	}
+	if depInstance == nil {
+		return reflect.Zero(dep.valueType), nil
+	}
+
+	return reflect.ValueOf(depInstance), nil
+}
+
+// instanceFor returns an instance for the key according to its lifetime:
+// singletons are created once per container, scoped instances are created once per scope
+// and transient instances are created on every call.
+func (c *Container) instanceFor(k key, scope *Scope) (any, error) {
+	ctor, exists := c.typesCtors[k]
+	if !exists {
+		return nil, fmt.Errorf("no constructor registered for %s", k)
+	}
+
+	switch ctor.lifetime {
+	case Scoped:
+		if scope == nil {
+			return nil, fmt.Errorf("scoped type %s must be resolved from a scope", k)
+		}
+		if instance, exists := scope.instances[k]; exists {
+			return instance, nil
+		}
+		instances, err := c.construct(ctor, scope)
+		if err != nil {
+			return nil, err
+		}
+		for i, r := range ctor.signature.results {
+			if c.typesCtors[r.key] == ctor {
+				scope.instances[r.key] = instances[i]
+			}
+		}
+		scope.created = append(scope.created, uniqueInstances(instances)...)
+		return scope.instances[k], nil
+
+	case Transient:
+		instances, err := c.construct(ctor, scope)
+		if err != nil {
+			return nil, err
+		}
+		if scope != nil {
+			scope.created = append(scope.created, uniqueInstances(instances)...)
+		}
+		return instances[ctor.resultIndex(k)], nil
+
+	default:
+		if instance, exists := c.instances[k]; exists {
+			return instance, nil
+		}
+		// singletons reachable only through lazy dependencies are built on first use
+		if err := c.resolveInstance(ctor); err != nil {
+			return nil, err
+		}
+		return c.instances[k], nil
+	}
+}
+
+// groupValue collects resolved group members into a slice in registration order
+func (c *Container) groupValue(dep dependency, scope *Scope) (reflect.Value, error) {
+	members := c.groupMembers(dep)
+	slice := reflect.MakeSlice(dep.key.typ, 0, len(members))
+	for _, member := range members {
+		instance, err := c.instanceFor(member, scope)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("group member %s not resolved: %w", member, err)
+		}
+		// a constructor returning a nil interface adds a nil member
+		if instance == nil {
+			slice = reflect.Append(slice, reflect.Zero(dep.key.typ.Elem()))
+			continue
+		}
+		slice = reflect.Append(slice, reflect.ValueOf(instance))
+	}
+	return slice, nil
+}
+
+// groupMembers returns members of the dependency group assignable to the slice element type.
+// Variadic parameters without a group take members of every group in registration order.
+func (c *Container) groupMembers(dep dependency) []key {
+	elemType := dep.key.typ.Elem()
+	candidates := c.groups[dep.group]
+	if dep.group == "" {
+		candidates = slices.DeleteFunc(slices.Clone(c.typeRegistry), func(k key) bool { return k.group == "" })
+	}
+
+	var members []key
+	for _, member := range candidates {
+		if !member.typ.AssignableTo(elemType) {
+			c.debugf("group member %s is not assignable to %s, skipping", member, elemType)
+			continue
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// dependencyKeys returns graph nodes the constructor depends on, group dependencies are expanded to their members.
+// Dependencies matching skip are left out.
+func (c *Container) dependencyKeys(ctor *constructorInfo, skip func(dependency) bool) []key {
+	keys := make([]key, 0, len(ctor.signature.args))
+	for _, dep := range ctor.signature.args {
+		// the context is not provided by constructors
+		if skip(dep) || dep.isContext {
+			continue
+		}
+		// optional dependencies nothing provides are not part of the graph
+		if _, exists := c.typesCtors[dep.key]; !exists && dep.optional {
+			continue
+		}
+		if dep.grouped() {
+			keys = append(keys, c.groupMembers(dep)...)
+			continue
+		}
+		keys = append(keys, dep.key)
+	}
+	return keys
+}
+
 // MustResolve is like Resolve but panics on error.
-func (c *Container) MustResolve(target any) {
-	if err := c.Resolve(target); err != nil {
+func (c *Container) MustResolve(target any, opts ...ResolveOption) {
+	if err := c.Resolve(target, opts...); err != nil {
 		panic(err)
 	}
 }
@@ -426,43 +1226,164 @@ func (c *Container) rebuildGraph() {
 	c.debugf("rebuilding dependency graph after interface resolution")
 
 	// Clear existing graph
-	c.graph.dependencies = make(map[reflect.Type][]reflect.Type)
-	c.graph.dependents = make(map[reflect.Type][]reflect.Type)
+	c.graph.dependencies = make(map[key][]key)
+	c.graph.dependents = make(map[key][]key)
+	c.graph.lazy = make(map[edge]bool)
+	c.graph.optional = make(map[edge]bool)
+	c.graph.decorated = make(map[edge]bool)
+	c.graph.detached = nil
 
 	// Rebuild based on resolved signatures
 	for typ, ctor := range c.typesCtors {
-		c.graph.dependencies[typ] = ctor.signature.args
-		for _, dep := range ctor.signature.args {
+		deps := c.dependencyKeys(ctor, func(dep dependency) bool { return dep.breaksCycle })
+		// decorators are applied before the instance is injected anywhere
+		for _, dep := range c.decoratorKeys(typ, func(dep dependency) bool { return dep.breaksCycle }) {
+			if !slices.Contains(deps, dep) {
+				c.graph.decorated[edge{from: typ, to: dep}] = true
+			}
+			deps = append(deps, dep)
+		}
+		c.graph.dependencies[typ] = deps
+		for _, dep := range deps {
 			c.graph.dependents[dep] = append(c.graph.dependents[dep], typ)
 		}
+
+		for _, dep := range ctor.signature.args {
+			e := edge{from: typ, to: dep.key}
+			if dep.lazy {
+				c.graph.lazy[e] = true
+				if dep.breaksCycle {
+					c.graph.detached = append(c.graph.detached, e)
+				}
+			}
+			if dep.optional {
+				c.graph.optional[e] = true
+				if _, exists := c.typesCtors[dep.key]; !exists {
+					c.debugf("optional dependency %s of %s is not provided", dep.key, typ)
+					c.graph.detached = append(c.graph.detached, e)
+				}
+			}
+		}
 	}
 
 	c.debugf("rebuilt dependencies: %v", c.graph.dependencies)
 	c.debugf("rebuilt dependents: %v", c.graph.dependents)
 }
 
-// validateDependencies checks if all dependencies have corresponding constructors
-func (c *Container) validateDependencies() error {
-	c.debugf("validating dependencies")
+// rootKeys returns types nothing depends on, types reachable only through lazy dependencies are not roots.
+func (c *Container) rootKeys() []key {
+	referenced := make(map[key]bool)
+	for typ := range c.graph.dependents {
+		referenced[typ] = true
+	}
+	for _, e := range c.graph.detached {
+		referenced[e.to] = true
+	}
 
-	requiredTypes := make(map[reflect.Type]bool)
+	var roots []key
+	for _, k := range c.typeRegistry {
+		if !referenced[k] {
+			roots = append(roots, k)
+		}
+	}
+	return roots
+}
 
-	// Get all dependencies from constructor signatures
-	for _, ctor := range c.constructors {
-		for _, depType := range ctor.signature.args {
-			requiredTypes[depType] = true
+// eagerKeys returns the roots together with their non-lazy dependencies, transitively.
+// Other types are built on demand, e.g. on first Get of a lazy dependency.
+func (c *Container) eagerKeys(roots []key) map[key]bool {
+	eager := make(map[key]bool)
+	var visit func(k key)
+	visit = func(k key) {
+		if eager[k] {
+			return
+		}
+		eager[k] = true
+		notLazy := func(dep dependency) bool { return dep.lazy }
+		for _, dep := range c.dependencyKeys(c.typesCtors[k], notLazy) {
+			visit(dep)
+		}
+		for _, dep := range c.decoratorKeys(k, notLazy) {
+			visit(dep)
 		}
 	}
+	for _, k := range roots {
+		visit(k)
+	}
+	return eager
+}
 
-	c.debugf("required dependency types: %v", requiredTypes)
+// validateLifetimes rejects singletons which capture scoped dependencies, directly or through transient ones.
+// It must be called after topologicalSort, so the graph is known to be acyclic.
+func (c *Container) validateLifetimes(nodes map[key]bool) error {
+	requiresScope := make(map[key]bool)
 
-	// Check if we have constructors for all required types
-	for depType := range requiredTypes {
-		if _, exists := c.typesCtors[depType]; !exists {
-			return fmt.Errorf("missing constructor for dependency type: %s", depType.String())
+	var visit func(k key) bool
+	visit = func(k key) bool {
+		if v, ok := requiresScope[k]; ok {
+			return v
+		}
+		ctor, exists := c.typesCtors[k]
+		if !exists {
+			// missing dependencies are reported by validateDependencies
+			return false
 		}
+		result := ctor.lifetime == Scoped
+		if ctor.lifetime == Transient {
+			for _, dep := range c.graph.dependencies[k] {
+				result = visit(dep) || result
+			}
+		}
+		requiresScope[k] = result
+		return result
 	}
-	return nil
+
+	var errs []error
+	for _, k := range c.typeRegistry {
+		if c.typesCtors[k].lifetime != Singleton || nodes != nil && !nodes[k] {
+			continue
+		}
+		for _, dep := range c.graph.dependencies[k] {
+			if visit(dep) {
+				errs = append(errs, fmt.Errorf("captive dependency: singleton %s depends on %s which requires a scope", k, dep))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateDependencies checks if all dependencies have corresponding constructors
+func (c *Container) validateDependencies(nodes map[key]bool) error {
+	c.debugf("validating dependencies")
+
+	// Check dependencies of the constructor signatures of the nodes, every missing one is reported
+	var errs []error
+	for _, ctor := range c.signaturesOf(nodes) {
+		errs = append(errs, c.missingDependencies(ctor)...)
+	}
+	return errors.Join(errs...)
+}
+
+// missingDependencies returns a MissingDependencyError for every required dependency of the constructor nothing provides
+func (c *Container) missingDependencies(ctor *constructorInfo) []error {
+	var errs []error
+	for _, dep := range ctor.signature.args {
+		// an empty group is a valid group
+		if dep.grouped() || dep.optional || dep.isContext {
+			continue
+		}
+		if _, exists := c.typesCtors[dep.key]; !exists {
+			errs = append(errs, &MissingDependencyError{
+				Dependency:  dep.key.String(),
+				Constructor: ctor.name,
+				Location:    ctor.location,
+				node:        ctor.node(),
+				missing:     dep.key,
+				counterpart: c.counterpart(dep.key),
+			})
+		}
+	}
+	return errs
 }
 
 const dotHeader string = `digraph DependencyGraph {
@@ -491,55 +1412,155 @@ func (c *Container) Visualize(filepath string) error {
 	b.WriteString(dotHeader)
 	b.WriteString("\n\n")
 
-	nodes := make(map[string]struct{})
-	edges := make(map[string][]string)
+	g := c.dependencyDot()
+	c.addDecoratorLayers(g)
+	g.writeNodes(&b)
+	c.writeTemplateClusters(&b)
+	b.WriteString("\n")
+	g.writeEdges(&b, c.edgeStyles())
+
+	// Close DOT graph
+	b.WriteString("}\n")
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		return fmt.Errorf("cannot write to dot file: %w", err)
+	}
+
+	return nil
+}
+
+// dotGraph holds the nodes and edges Visualize draws
+type dotGraph struct {
+	nodes map[string]struct{}
+	edges map[string][]string
+	// nodes of optional dependencies nothing provides
+	missing map[string]bool
+	// nodes of decorator layers
+	layers map[string]bool
+}
+
+func (g *dotGraph) addEdge(from, to string) {
+	g.nodes[from] = struct{}{}
+	g.nodes[to] = struct{}{}
+	g.edges[from] = append(g.edges[from], to)
+}
+
+// dependencyDot collects the dependency graph, edges of decorated nodes are left to addDecoratorLayers
+func (c *Container) dependencyDot() *dotGraph {
+	g := &dotGraph{
+		nodes:   make(map[string]struct{}),
+		edges:   make(map[string][]string),
+		missing: make(map[string]bool),
+		layers:  make(map[string]bool),
+	}
+
+	for _, e := range c.graph.detached {
+		g.addEdge(e.from.String(), e.to.String())
+		if _, exists := c.typesCtors[e.to]; !exists {
+			g.missing[e.to.String()] = true
+		}
+	}
 
 	// Process dependencies
 	for componentName, deps := range c.graph.dependencies {
-		from := componentName.String()
-		nodes[from] = struct{}{}
+		g.nodes[componentName.String()] = struct{}{}
 		for _, dep := range deps {
-			to := dep.String()
-			nodes[to] = struct{}{}
-			edges[from] = append(edges[from], to)
+			if !c.graph.decorated[edge{from: componentName, to: dep}] {
+				g.addEdge(componentName.String(), dep.String())
+			}
 		}
 	}
 
 	// Process dependents (reverse dependencies)
 	for componentName, dependents := range c.graph.dependents {
-		to := componentName.String()
-		nodes[to] = struct{}{}
+		g.nodes[componentName.String()] = struct{}{}
 		for _, dep := range dependents {
-			from := dep.String()
-			nodes[from] = struct{}{}
-			edges[from] = append(edges[from], to)
+			if !c.graph.decorated[edge{from: dep, to: componentName}] {
+				g.addEdge(dep.String(), componentName.String())
+			}
 		}
 	}
+	return g
+}
 
-	for nodeName := range nodes {
-		fmt.Fprintf(&b, "    %q;\n", nodeName)
+// addDecoratorLayers draws decorator layers, the outermost first, between the decorated node and decorator dependencies
+func (c *Container) addDecoratorLayers(g *dotGraph) {
+	for k, decorators := range c.decorators {
+		from := k.String()
+		for i := len(decorators) - 1; i >= 0; i-- {
+			d := decorators[i]
+			layer := fmt.Sprintf("%s (decorator %d)", k, i+1)
+			g.layers[layer] = true
+			g.addEdge(from, layer)
+			for _, dep := range c.dependencyKeys(d.constructorInfo, d.isDecorated) {
+				g.addEdge(layer, dep.String())
+			}
+			from = layer
+		}
 	}
+}
 
-	b.WriteString("\n")
+// edgeStyles returns the DOT style of lazy, optional and binding edges
+func (c *Container) edgeStyles() map[string]string {
+	styles := make(map[string]string)
+	for e := range c.graph.lazy {
+		styles[e.from.String()+"->"+e.to.String()] = "dashed"
+	}
+	for e := range c.graph.optional {
+		styles[e.from.String()+"->"+e.to.String()] = "dotted"
+	}
+	for iface, impl := range c.bindings {
+		styles[iface.String()+"->"+impl.String()] = "bold"
+	}
+	return styles
+}
+
+// writeTemplateClusters groups the instantiations of each template in a cluster, they stay separate nodes
+func (c *Container) writeTemplateClusters(b *strings.Builder) {
+	templates := make(map[string][]string)
+	for k, ctor := range c.typesCtors {
+		if ctor.template != "" {
+			templates[ctor.template] = append(templates[ctor.template], k.String())
+		}
+	}
+	for i, name := range slices.Sorted(maps.Keys(templates)) {
+		fmt.Fprintf(b, "\n    subgraph cluster_%d {\n        label=%q;\n        style=dashed;\n", i, name)
+		slices.Sort(templates[name])
+		for _, nodeName := range templates[name] {
+			fmt.Fprintf(b, "        %q;\n", nodeName)
+		}
+		b.WriteString("    }\n")
+	}
+}
+
+func (g *dotGraph) writeNodes(b *strings.Builder) {
+	for nodeName := range g.nodes {
+		switch {
+		case g.layers[nodeName]:
+			fmt.Fprintf(b, "    %q [style=\"rounded,filled\", fillcolor=lightgrey];\n", nodeName)
+		case g.missing[nodeName]:
+			fmt.Fprintf(b, "    %q [style=\"rounded,dotted\"];\n", nodeName)
+		default:
+			fmt.Fprintf(b, "    %q;\n", nodeName)
+		}
+	}
+}
 
+// writeEdges writes every edge once, with its style if it has one
+func (g *dotGraph) writeEdges(b *strings.Builder, styles map[string]string) {
 	addedEdges := make(map[string]struct{})
-	for from, toList := range edges {
+	for from, toList := range g.edges {
 		for _, to := range toList {
 			edgeKey := from + "->" + to
 			if _, exists := addedEdges[edgeKey]; exists {
 				continue
 			}
-			fmt.Fprintf(&b, "    %q -> %q;\n", from, to)
+			if style, ok := styles[edgeKey]; ok {
+				fmt.Fprintf(b, "    %q -> %q [style=%s];\n", from, to, style)
+			} else {
+				fmt.Fprintf(b, "    %q -> %q;\n", from, to)
+			}
 			addedEdges[edgeKey] = struct{}{}
 		}
 	}
-
-	// Close DOT graph
-	b.WriteString("}\n")
-
-	if _, err := f.WriteString(b.String()); err != nil {
-		return fmt.Errorf("cannot write to dot file: %w", err)
-	}
-
-	return nil
 }
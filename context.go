@@ -0,0 +1,86 @@
+package compoapp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var contextType = reflect.TypeFor[context.Context]()
+
+// ResolveContext is like Resolve, constructors declaring a context.Context parameter receive ctx.
+//
+// Once ctx is done no further constructor is called. A running constructor is abandoned:
+// its results are dropped and the returned ConstructorError names it and wraps ctx.Err().
+func (c *Container) ResolveContext(ctx context.Context, target any, opts ...ResolveOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.resolveContext(ctx, nil, target, opts...)
+}
+
+// ResolveContext resolves the target like Container.ResolveContext, building scoped instances in this scope
+func (s *Scope) ResolveContext(ctx context.Context, target any, opts ...ResolveOption) error {
+	c := s.container
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("scope is closed")
+	}
+
+	return c.resolveContext(ctx, s, target, opts...)
+}
+
+// resolveContext runs resolve with ctx injected into constructors
+func (c *Container) resolveContext(ctx context.Context, scope *Scope, target any, opts ...ResolveOption) error {
+	c.ctx = ctx
+	defer func() { c.ctx = nil }()
+
+	return c.resolve(scope, target, opts...)
+}
+
+// resolutionContext returns the context of the running ResolveContext call, background otherwise,
+// e.g. for Invoke or lazy dependencies built after resolution
+func (c *Container) resolutionContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// callContext calls fn unless ctx is done, and stops waiting for it once ctx is done
+func callContext(ctx context.Context, fn func() ([]reflect.Value, error)) ([]reflect.Value, error) {
+	if ctx.Done() == nil {
+		return fn()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type outcome struct {
+		results []reflect.Value
+		err     error
+	}
+	done := make(chan outcome, 1)
+	// panics are not recovered here: fn returns them as errors unless the container crashes on panic,
+	// then the crash shows the stack of the constructor
+	go func() {
+		results, err := fn()
+		done <- outcome{results, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.results, o.err
+	case <-ctx.Done():
+		// a constructor which has just returned is not abandoned
+		select {
+		case o := <-done:
+			return o.results, o.err
+		default:
+		}
+		// the constructor keeps running in the background, its results are dropped
+		return nil, ctx.Err()
+	}
+}
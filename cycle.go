@@ -0,0 +1,174 @@
+package compoapp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Cycle is a chain of dependencies leading back to its first type
+type Cycle struct {
+	// Types in dependency order, each depends on the next one and the last one on the first
+	Types []string
+	// Constructors providing the types, in the same order
+	Constructors []string
+
+	keys []key
+}
+
+// String describes the cycle as a chain of types with their constructors, ending with the first type
+func (cy Cycle) String() string {
+	var b strings.Builder
+	for i, typ := range cy.Types {
+		fmt.Fprintf(&b, "%s (%s) -> ", typ, cy.Constructors[i])
+	}
+	b.WriteString(cy.Types[0])
+	return b.String()
+}
+
+// DOT returns a graphviz snippet with the cycles highlighted
+func (e *CycleError) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph Cycles {\n    rankdir=LR;\n    node [shape=box, style=rounded, fontname=\"Arial\", color=red];\n\n")
+	for _, cycle := range e.Cycles {
+		for i, typ := range cycle.Types {
+			fmt.Fprintf(&b, "    %q -> %q [color=red];\n", typ, cycle.Types[(i+1)%len(cycle.Types)])
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid returns a mermaid flowchart with the cycles highlighted
+func (e *CycleError) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string)
+	var nodes []string
+	for _, cycle := range e.Cycles {
+		for _, typ := range cycle.Types {
+			ids[typ] = fmt.Sprintf("n%d", len(nodes))
+			nodes = append(nodes, ids[typ])
+			fmt.Fprintf(&b, "    %s[%q]\n", ids[typ], typ)
+		}
+	}
+	for _, cycle := range e.Cycles {
+		for i, typ := range cycle.Types {
+			fmt.Fprintf(&b, "    %s --> %s\n", ids[typ], ids[cycle.Types[(i+1)%len(cycle.Types)]])
+		}
+	}
+
+	b.WriteString("    classDef cycle stroke:#f00,stroke-width:2px;\n")
+	fmt.Fprintf(&b, "    class %s cycle;\n", strings.Join(nodes, ","))
+	return b.String()
+}
+
+// findCycles returns one cycle for every strongly connected component of the nodes which has a cycle.
+// Cycles are independent: no type belongs to two of them.
+func (c *Container) findCycles(nodes map[key]bool) []Cycle {
+	// Tarjan's algorithm for strongly connected components
+	index := make(map[key]int)
+	lowlink := make(map[key]int)
+	onStack := make(map[key]bool)
+	var stack []key
+	var components [][]key
+
+	var connect func(k key)
+	connect = func(k key) {
+		index[k] = len(index)
+		lowlink[k] = index[k]
+		stack = append(stack, k)
+		onStack[k] = true
+
+		for _, dep := range c.graph.dependencies[k] {
+			if !nodes[dep] {
+				continue
+			}
+			if _, visited := index[dep]; !visited {
+				connect(dep)
+				lowlink[k] = min(lowlink[k], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[k] = min(lowlink[k], index[dep])
+			}
+		}
+
+		if lowlink[k] != index[k] {
+			return
+		}
+		var component []key
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == k {
+				break
+			}
+		}
+		components = append(components, component)
+	}
+
+	sorted := make([]key, 0, len(nodes))
+	for k := range nodes {
+		sorted = append(sorted, k)
+	}
+	slices.SortFunc(sorted, compareKeys)
+	for _, k := range sorted {
+		if _, visited := index[k]; !visited {
+			connect(k)
+		}
+	}
+
+	var cycles []Cycle
+	for _, component := range components {
+		members := make(map[key]bool, len(component))
+		for _, k := range component {
+			members[k] = true
+		}
+		start := slices.MinFunc(component, compareKeys)
+		path := c.cycleFrom(start, members)
+		if path == nil {
+			continue
+		}
+
+		cycle := Cycle{keys: path}
+		for _, k := range path {
+			cycle.Types = append(cycle.Types, k.String())
+			cycle.Constructors = append(cycle.Constructors, c.typesCtors[k].name)
+		}
+		cycles = append(cycles, cycle)
+	}
+	slices.SortFunc(cycles, func(a, b Cycle) int { return compareKeys(a.keys[0], b.keys[0]) })
+	return cycles
+}
+
+// cycleFrom returns the shortest chain of dependencies among members leading from start back to it,
+// nil when there is none
+func (c *Container) cycleFrom(start key, members map[key]bool) []key {
+	parents := map[key]key{start: {}}
+	queue := []key{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range c.graph.dependencies[current] {
+			if dep == start {
+				path := []key{current}
+				for current != start {
+					current = parents[current]
+					path = append([]key{current}, path...)
+				}
+				return path
+			}
+			if _, seen := parents[dep]; !seen && members[dep] {
+				parents[dep] = current
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return nil
+}
+
+func compareKeys(a, b key) int {
+	return strings.Compare(a.String(), b.String())
+}
@@ -0,0 +1,188 @@
+package compoapp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// decorator wraps instances of a provided type
+type decorator struct {
+	*constructorInfo
+	// index of the dependency receiving the decorated instance
+	decorated int
+}
+
+// isDecorated reports whether the dependency receives the decorated instance
+func (d *decorator) isDecorated(dep dependency) bool {
+	decorated := d.signature.args[d.decorated]
+	return dep.param == decorated.param && dep.field == nil
+}
+
+// MustDecorate registers a decorator and panics on error
+func (c *Container) MustDecorate(fn any, opts ...ProvideOption) {
+	if err := c.Decorate(fn, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// Decorate registers a function wrapping instances of an already provided type,
+// e.g. func(repo IUserRepository, m *Metrics) IUserRepository.
+//
+// The decorator returns T or (T, error) and accepts the original T along with its own dependencies.
+// Decorators of the same type are chained in registration order and applied before the instance
+// is injected anywhere. Use Name to decorate a named type. Decorating an interface implemented by
+// a provided type decorates it wherever the interface is injected.
+func (c *Container) Decorate(fn any, opts ...ProvideOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var options provideOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.group != "" {
+		return fmt.Errorf("value groups cannot be decorated")
+	}
+
+	decoratorValue := reflect.ValueOf(fn)
+	if decoratorValue.Kind() != reflect.Func {
+		return fmt.Errorf("decorator must be a function")
+	}
+
+	decoratorType := decoratorValue.Type()
+	c.debugf("decorator %s", decoratorType.String())
+	signature, err := c.analyzeFunction(decoratorType)
+	if err != nil {
+		return fmt.Errorf("failed to analyze decorator: %w", err)
+	}
+	if err := applyParamTags(signature.args, options.paramTags, decoratorType.NumIn()); err != nil {
+		return fmt.Errorf("failed to analyze decorator: %w", err)
+	}
+	if len(signature.results) != 1 || signature.results[0].field != nil {
+		return fmt.Errorf("decorator must return (T) or (T, error)")
+	}
+
+	k := key{typ: signature.results[0].key.typ, name: options.name}
+	if _, built := c.instances[k]; built {
+		return fmt.Errorf("cannot decorate %s: it is already constructed", k)
+	}
+	decorated := -1
+	for i, dep := range signature.args {
+		if dep.field == nil && dep.valueType == k.typ && !dep.grouped() {
+			dep.key = k
+			signature.args[i] = dep
+			decorated = i
+			break
+		}
+	}
+	if decorated < 0 {
+		return fmt.Errorf("decorator must accept the decorated %s", k.typ)
+	}
+
+	// the decorated instance is never replaced with an implementation
+	dependNeedsResolution := interfaceDependencies(signature.args)
+	dependNeedsResolution[decorated] = false
+
+	signature.results[0].key = k
+	c.decorators[k] = append(c.decorators[k], &decorator{
+		constructorInfo: &constructorInfo{
+			fn:                    fn,
+			name:                  functionName(fn),
+			signature:             signature,
+			dependNeedsResolution: dependNeedsResolution,
+			location:              callerLocation(),
+		},
+		decorated: decorated,
+	})
+
+	return nil
+}
+
+// decorate applies decorators of the key to the instance in registration order
+func (c *Container) decorate(k key, instance any, scope *Scope) (any, error) {
+	for i, d := range c.decorators[k] {
+		value := reflect.ValueOf(instance)
+		if !value.IsValid() {
+			value = reflect.Zero(k.typ)
+		}
+
+		args, err := c.constructorArgs(d.constructorInfo, scope, d.decorated, value)
+		if err != nil {
+			return nil, fmt.Errorf("decorator %d of %s: %w", i+1, k, err)
+		}
+		results, err := c.call(d.constructorInfo, args)
+		if err != nil {
+			return nil, fmt.Errorf("decorator %d of %s: %w", i+1, k, constructorError(d.constructorInfo, k, err))
+		}
+		instance = results[0].Interface()
+	}
+	return instance, nil
+}
+
+// decoratorKeys returns graph nodes the decorators of the key depend on, the decorated instance is left out.
+// Dependencies matching skip are left out too.
+func (c *Container) decoratorKeys(k key, skip func(dependency) bool) []key {
+	var keys []key
+	for _, d := range c.decorators[k] {
+		keys = append(keys, c.dependencyKeys(d.constructorInfo, func(dep dependency) bool {
+			return d.isDecorated(dep) || skip(dep)
+		})...)
+	}
+	return keys
+}
+
+// signatures returns constructors followed by decorators, both have dependencies to resolve
+func (c *Container) signatures() []*constructorInfo {
+	all := append([]*constructorInfo{}, c.constructors...)
+	for _, layers := range c.decorators {
+		for _, d := range layers {
+			all = append(all, d.constructorInfo)
+		}
+	}
+	return all
+}
+
+// signaturesOf returns the constructors and decorators of the nodes, every one for nil nodes
+func (c *Container) signaturesOf(nodes map[key]bool) []*constructorInfo {
+	if nodes == nil {
+		return c.signatures()
+	}
+	checked := make(map[*constructorInfo]bool)
+	for k := range nodes {
+		if ctor, exists := c.typesCtors[k]; exists {
+			checked[ctor] = true
+		}
+		for _, d := range c.decorators[k] {
+			checked[d.constructorInfo] = true
+		}
+	}
+	return slices.DeleteFunc(c.signatures(), func(ctor *constructorInfo) bool { return !checked[ctor] })
+}
+
+// resolveDecorated checks that every decorated type is provided.
+// An interface without a direct constructor is bound to its single implementation
+// by an alias constructor, so the decorated instance is injected wherever the interface is.
+func (c *Container) resolveDecorated() error {
+	var errs []error
+	for k := range c.decorators {
+		if _, exists := c.typesCtors[k]; exists {
+			continue
+		}
+
+		var implementations []key
+		if k.typ.Kind() == reflect.Interface {
+			implementations = c.implementationsOf(k)
+		}
+		switch len(implementations) {
+		case 0:
+			errs = append(errs, fmt.Errorf("nothing provides decorated %s", k))
+		case 1:
+			c.alias(k, implementations[0])
+		default:
+			errs = append(errs, c.ambiguousBinding(c.decorators[k][0].constructorInfo, k, implementations))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,273 @@
+package compoapp
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// MissingDependencyError reports a dependency nothing provides
+type MissingDependencyError struct {
+	// Dependency is the type nothing provides, with its name if any
+	Dependency string
+	// Constructor requires the dependency, empty when the dependency is the resolved target
+	Constructor string
+	// Location is the Provide call site of the constructor, if known
+	Location string
+	// Path is the dependency chain from the requested root to the missing dependency
+	Path []string
+
+	// node of the constructor and the missing key, used to trace Path
+	node, missing key
+	// the missing dependency is an interface nothing implements
+	iface bool
+	// provided pointer or value counterpart of the missing type, if any
+	counterpart string
+}
+
+func (e *MissingDependencyError) Error() string {
+	switch {
+	case e.Constructor == "":
+		return fmt.Sprintf("no instance found for type %s%s", e.Dependency, didYouMean(e.counterpart))
+	case e.iface:
+		return fmt.Sprintf("no implementation found for interface %s, required by %s%s%s",
+			e.Dependency, e.Constructor, atLocation(e.Location), formatPath(e.Path))
+	default:
+		return fmt.Sprintf("missing constructor for dependency type: %s, required by %s%s%s%s",
+			e.Dependency, e.Constructor, atLocation(e.Location), formatPath(e.Path), didYouMean(e.counterpart))
+	}
+}
+
+// didYouMean suggests the provided counterpart of a missing type: T and *T are not interchangeable
+func didYouMean(counterpart string) string {
+	if counterpart == "" {
+		return ""
+	}
+	return fmt.Sprintf(", %s is provided, request it instead", counterpart)
+}
+
+// CycleError reports dependencies which cannot be ordered because they form cycles
+type CycleError struct {
+	// Cycles lists every independent cycle
+	Cycles []Cycle
+	// Path is the dependency chain from the requested root to the first type of the first cycle
+	Path []string
+
+	node key
+}
+
+func (e *CycleError) Error() string {
+	cycles := make([]string, 0, len(e.Cycles))
+	for _, cycle := range e.Cycles {
+		cycles = append(cycles, cycle.String())
+	}
+	return fmt.Sprintf("circular dependency detected: %s%s", strings.Join(cycles, "; "), formatPath(e.Path))
+}
+
+// AmbiguousBindingError reports an interface several provided types implement
+type AmbiguousBindingError struct {
+	Interface       string
+	Implementations []string
+	// Constructor requires the interface
+	Constructor string
+	// Location is the Provide call site of the constructor, if known
+	Location string
+	// Path is the dependency chain from the requested root to the interface
+	Path []string
+
+	node, iface key
+}
+
+func (e *AmbiguousBindingError) Error() string {
+	return fmt.Sprintf("multiple implementations found for interface %s: [%s], use Bind or Primary to choose one, required by %s%s%s",
+		e.Interface, strings.Join(e.Implementations, " "), e.Constructor, atLocation(e.Location), formatPath(e.Path))
+}
+
+// ConstructorError reports an error returned by a constructor or a decorator
+type ConstructorError struct {
+	Constructor string
+	// Location is the Provide call site of the constructor, if known
+	Location string
+	// Path is the dependency chain from the requested root to the types the constructor provides
+	Path []string
+	Err  error
+
+	node key
+}
+
+func (e *ConstructorError) Error() string {
+	return fmt.Sprintf("constructor %s%s failed%s: %v", e.Constructor, atLocation(e.Location), formatPath(e.Path), e.Err)
+}
+
+func (e *ConstructorError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError reports a panic raised by a constructor, a decorator or an invoked function, see CrashOnPanic
+type PanicError struct {
+	Constructor string
+	// Location is the Provide call site of the constructor, if known
+	Location string
+	// Path is the dependency chain from the requested root to the types the constructor provides
+	Path []string
+	// Value passed to panic
+	Value any
+	// Stack of the panicking goroutine
+	Stack []byte
+
+	node key
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("constructor %s%s panicked%s: %v", e.Constructor, atLocation(e.Location), formatPath(e.Path), e.Value)
+}
+
+// Unwrap returns the panic value when it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// constructorError wraps an error returned by the constructor providing node, panics are kept as they are
+func constructorError(ctor *constructorInfo, node key, err error) error {
+	if panicErr, ok := err.(*PanicError); ok {
+		panicErr.node = node
+		return panicErr
+	}
+	return &ConstructorError{Constructor: ctor.name, Location: ctor.location, Err: err, node: node}
+}
+
+func atLocation(location string) string {
+	if location == "" {
+		return ""
+	}
+	return " provided at " + location
+}
+
+func formatPath(path []string) string {
+	if len(path) < 2 {
+		return ""
+	}
+	return " (path: " + strings.Join(path, " -> ") + ")"
+}
+
+// tracePaths fills Path of the typed errors within err with the chain from the target to the failing node.
+// Without a target, chains start from a root of the graph.
+func (c *Container) tracePaths(err error, target key) {
+	switch e := err.(type) {
+	case *MissingDependencyError:
+		if e.Path == nil {
+			e.Path = c.dependencyPath(target, e.node, e.missing)
+		}
+	case *CycleError:
+		if e.Path == nil {
+			e.Path = c.dependencyPath(target, e.node)
+		}
+	case *AmbiguousBindingError:
+		if e.Path == nil {
+			e.Path = c.dependencyPath(target, e.node, e.iface)
+		}
+	case *ConstructorError:
+		if e.Path == nil {
+			e.Path = c.dependencyPath(target, e.node)
+		}
+	case *PanicError:
+		if e.Path == nil {
+			e.Path = c.dependencyPath(target, e.node)
+		}
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := u.Unwrap(); inner != nil {
+			c.tracePaths(inner, target)
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range u.Unwrap() {
+			c.tracePaths(inner, target)
+		}
+	}
+}
+
+// dependencyPath returns the shortest chain of dependencies from the target to the node, followed by tail.
+// A node the target does not depend on is reached from a root of the graph.
+func (c *Container) dependencyPath(target, node key, tail ...key) []string {
+	var path []key
+	if node.typ != nil {
+		roots := c.rootKeys()
+		if target.typ != nil {
+			roots = append([]key{target}, roots...)
+		}
+		path = c.shortestPath(roots, node)
+	}
+	path = append(path, tail...)
+
+	chain := make([]string, 0, len(path))
+	for _, k := range path {
+		chain = append(chain, k.String())
+	}
+	return chain
+}
+
+// shortestPath finds the chain from the first root reaching the node using breadth-first search
+func (c *Container) shortestPath(roots []key, node key) []key {
+	for _, root := range roots {
+		parents := map[key]key{root: {}}
+		queue := []key{root}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			if current != node {
+				for _, dep := range c.graph.dependencies[current] {
+					if _, seen := parents[dep]; !seen {
+						parents[dep] = current
+						queue = append(queue, dep)
+					}
+				}
+				continue
+			}
+
+			path := []key{node}
+			for current != root {
+				current = parents[current]
+				path = append([]key{current}, path...)
+			}
+			return path
+		}
+	}
+	return []key{node}
+}
+
+var packagePath = reflect.TypeFor[Container]().PkgPath()
+
+// callerLocation returns file:line of the first caller outside the package, e.g. of Provide
+func callerLocation() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePath+".") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// anonymousFunc matches the names the compiler gives to function literals, e.g. app.main.func1.2
+var anonymousFunc = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+
+// functionName returns the name of the function without its package path, e.g. app.NewDatabase.
+// Function literals and functions built with reflect have no name of their own and are named by their signature.
+func functionName(fn any) string {
+	value := reflect.ValueOf(fn)
+	f := runtime.FuncForPC(value.Pointer())
+	if f == nil || strings.HasPrefix(f.Name(), "reflect.") || anonymousFunc.MatchString(f.Name()) {
+		return value.Type().String()
+	}
+	return f.Name()[strings.LastIndex(f.Name(), "/")+1:]
+}
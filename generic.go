@@ -0,0 +1,101 @@
+package compoapp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resolver resolves instances, it is implemented by Container and Scope
+type Resolver interface {
+	Resolve(target any, opts ...ResolveOption) error
+}
+
+var (
+	_ Resolver = (*Container)(nil)
+	_ Resolver = (*Scope)(nil)
+)
+
+// Get resolves an instance of T, e.g. Get[*Server](container)
+func Get[T any](r Resolver, opts ...ResolveOption) (T, error) {
+	var value T
+	err := r.Resolve(&value, opts...)
+	return value, err
+}
+
+// MustGet is like Get but panics on error
+func MustGet[T any](r Resolver, opts ...ResolveOption) T {
+	value, err := Get[T](r, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// ProvideFunc registers a constructor which must provide T, e.g. ProvideFunc[IUserRepository](c, NewUserRepository).
+//
+// The constructor provides T when one of its results, or a field of an Out result struct, is T
+// or a type assignable to it. Assignable results are bound to T with As.
+func ProvideFunc[T any](c *Container, constructor any, opts ...ProvideOption) error {
+	typ := reflect.TypeFor[T]()
+
+	fnType := reflect.TypeOf(constructor)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return fmt.Errorf("constructor must be a function")
+	}
+	provides, assignable := providesType(fnType, typ)
+	if !provides && !assignable {
+		return fmt.Errorf("constructor %s does not provide %s", fnType, typ)
+	}
+	if !provides {
+		opts = append(opts, As(new(T)))
+	}
+
+	return c.Provide(constructor, opts...)
+}
+
+// MustProvideFunc is like ProvideFunc but panics on error
+func MustProvideFunc[T any](c *Container, constructor any, opts ...ProvideOption) {
+	if err := ProvideFunc[T](c, constructor, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// providesType reports whether the function returns typ, or else returns a type assignable to it
+func providesType(fnType, typ reflect.Type) (provides bool, assignable bool) {
+	check := func(resultType reflect.Type) {
+		provides = provides || resultType == typ
+		assignable = assignable || resultType.AssignableTo(typ)
+	}
+	for i := 0; i < fnType.NumOut(); i++ {
+		resultType := fnType.Out(i)
+		if !isOut(resultType) {
+			check(resultType)
+			continue
+		}
+		for j := 0; j < resultType.NumField(); j++ {
+			check(resultType.Field(j).Type)
+		}
+	}
+	return provides, assignable
+}
+
+// ResolveType resolves an instance of the given type, for code which knows the type only at runtime
+func (c *Container) ResolveType(typ reflect.Type, opts ...ResolveOption) (any, error) {
+	return resolveType(c, typ, opts...)
+}
+
+// ResolveType resolves an instance of the given type like Container.ResolveType, building scoped instances in this scope
+func (s *Scope) ResolveType(typ reflect.Type, opts ...ResolveOption) (any, error) {
+	return resolveType(s, typ, opts...)
+}
+
+func resolveType(r Resolver, typ reflect.Type, opts ...ResolveOption) (any, error) {
+	if typ == nil {
+		return nil, fmt.Errorf("cannot resolve untyped nil")
+	}
+	target := reflect.New(typ)
+	if err := r.Resolve(target.Interface(), opts...); err != nil {
+		return nil, err
+	}
+	return target.Elem().Interface(), nil
+}
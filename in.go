@@ -0,0 +1,61 @@
+package compoapp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// In marks a parameter struct whose exported fields are injected one by one.
+//
+// Embed it into a struct and take the struct as a constructor parameter:
+//
+//	type UserServiceParams struct {
+//		compoapp.In
+//
+//		DB       *sql.DB   `name:"replica"`
+//		Cache    *Cache    `optional:"true"`
+//		Handlers []Handler `group:"handlers"`
+//	}
+//
+//	func NewUserService(p UserServiceParams) *UserService
+//
+// Fields accept the same tags as ParamTags.
+type In struct{}
+
+var inType = reflect.TypeFor[In]()
+
+// isIn reports whether the parameter type is a struct embedding In
+func isIn(paramType reflect.Type) bool {
+	if paramType.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < paramType.NumField(); i++ {
+		field := paramType.Field(i)
+		if field.Anonymous && field.Type == inType {
+			return true
+		}
+	}
+	return false
+}
+
+// inFields returns a dependency for every field of the In parameter struct
+func inFields(paramType reflect.Type, param int) ([]dependency, error) {
+	deps := make([]dependency, 0, paramType.NumField())
+	for i := 0; i < paramType.NumField(); i++ {
+		field := paramType.Field(i)
+		if field.Anonymous && field.Type == inType {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("field %s of %s must be exported to be injected", field.Name, paramType)
+		}
+
+		dep := newDependency(field.Type, param)
+		dep.field = field.Index
+		if err := applyTag(&dep, field.Tag); err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", field.Name, paramType, err)
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
@@ -0,0 +1,99 @@
+package compoapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// MustInvoke is like Invoke but panics on error
+func (c *Container) MustInvoke(fn any) {
+	if err := c.Invoke(fn); err != nil {
+		panic(err)
+	}
+}
+
+// Invoke calls fn with its parameters injected and returns its error, if fn returns one.
+// Use it to run setup code, e.g. registering routes or running migrations.
+//
+// Parameters are analyzed like constructor parameters and only dependencies fn needs are built.
+// fn may return nothing or an error.
+func (c *Container) Invoke(fn any) error {
+	return c.invoke(context.Background(), nil, fn)
+}
+
+// invoke calls fn with ctx injected like ResolveContext does, scoped dependencies are taken from scope,
+// which is nil for the root container.
+// The lock is held only while the arguments are built, so fn may call Lazy and Provider values, which take it.
+func (c *Container) invoke(ctx context.Context, scope *Scope, fn any) error {
+	c.mu.Lock()
+	c.ctx = ctx
+	info, args, err := c.invokeArgs(scope, fn)
+	c.ctx = nil
+	crashOnPanic := c.crashOnPanic
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = callIn(ctx, crashOnPanic, info, args)
+	return err
+}
+
+// invokeArgs checks fn and builds its arguments
+func (c *Container) invokeArgs(scope *Scope, fn any) (info *constructorInfo, values []reflect.Value, err error) {
+	if scope != nil && scope.closed {
+		return nil, nil, fmt.Errorf("scope is closed")
+	}
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func {
+		return nil, nil, fmt.Errorf("invoked value must be a function")
+	}
+
+	fnType := fnValue.Type()
+	c.debugf("invoking %s", fnType.String())
+	if fnType.NumOut() > 1 || fnType.NumOut() == 1 && fnType.Out(0) != errorType {
+		return nil, nil, fmt.Errorf("invoked function must return nothing or an error")
+	}
+
+	args, err := c.analyzeParams(fnType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to analyze invoked function: %w", err)
+	}
+	info = &constructorInfo{
+		fn:                    fn,
+		name:                  functionName(fn),
+		signature:             fnSignature{args: args, hasError: fnType.NumOut() == 1},
+		dependNeedsResolution: interfaceDependencies(args),
+		location:              callerLocation(),
+	}
+	defer func() {
+		if err != nil {
+			c.tracePaths(err, key{})
+		}
+	}()
+
+	// only the types fn reaches are checked, its interface dependencies reach their implementations
+	sortedTypes, err := c.prepare(c.dependencyKeys(info, func(dependency) bool { return false }))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.resolveDependencies(info); err != nil {
+		return nil, nil, fmt.Errorf("interface resolution failed: %w", err)
+	}
+	if err := errors.Join(c.missingDependencies(info)...); err != nil {
+		return nil, nil, err
+	}
+
+	values, err = c.constructorArgs(info, scope, -1, reflect.Value{})
+	if err != nil {
+		return nil, nil, err
+	}
+	// singletons built for fn are initialized and started by a LifecycleRunner too
+	notLazy := func(dep dependency) bool { return dep.lazy }
+	if err := c.appendSorted(sortedTypes, c.eagerKeys(c.dependencyKeys(info, notLazy))); err != nil {
+		return nil, nil, err
+	}
+	return info, values, nil
+}
@@ -0,0 +1,98 @@
+package compoapp
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Lazy defers construction of T until the first Get call.
+//
+// Declare a Lazy[T] constructor parameter to receive it. Lazy dependencies still take part
+// in cycle detection unless tagged with `cycle:"break"`, see ParamTags.
+// Instances built by Get do not take part in the lifecycle.
+type Lazy[T any] struct {
+	state *lazyState
+}
+
+type lazyState struct {
+	once  sync.Once
+	build func() (any, error)
+	value any
+	err   error
+}
+
+// Get constructs the value on the first call and returns the same value and error afterwards.
+// It must not be called from a constructor.
+func (l Lazy[T]) Get() (T, error) {
+	var zero T
+	if l.state == nil {
+		return zero, fmt.Errorf("lazy %s was not injected by the container", reflect.TypeFor[T]())
+	}
+
+	l.state.once.Do(func() {
+		l.state.value, l.state.err = l.state.build()
+	})
+	if l.state.err != nil {
+		return zero, l.state.err
+	}
+
+	value, _ := l.state.value.(T)
+	return value, nil
+}
+
+// MustGet is like Get but panics on error.
+func (l Lazy[T]) MustGet() T {
+	value, err := l.Get()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func (Lazy[T]) lazyType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (l *Lazy[T]) bind(state *lazyState) {
+	l.state = state
+}
+
+// lazyParam is implemented by every *Lazy[T], so the container can recognize and fill them
+type lazyParam interface {
+	lazyType() reflect.Type
+	bind(state *lazyState)
+}
+
+var lazyParamType = reflect.TypeFor[lazyParam]()
+
+// lazyElem returns T for a Lazy[T] parameter type
+func lazyElem(paramType reflect.Type) (reflect.Type, bool) {
+	if paramType.Kind() != reflect.Struct || !reflect.PointerTo(paramType).Implements(lazyParamType) {
+		return nil, false
+	}
+	return reflect.New(paramType).Interface().(lazyParam).lazyType(), true
+}
+
+// lazyValue creates a Lazy[T] value of paramType which builds the dependency on first Get
+func (c *Container) lazyValue(paramType reflect.Type, dep dependency, ctor *constructorInfo, scope *Scope) reflect.Value {
+	state := &lazyState{build: func() (any, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if scope != nil && scope.closed {
+			return nil, fmt.Errorf("lazy %s required by %s: scope is closed", dep.key, ctor)
+		}
+
+		c.debugf("building lazy %s required by %s", dep.key, ctor)
+		instance, err := c.instanceFor(dep.key, scope)
+		if err != nil {
+			return nil, fmt.Errorf("lazy %s required by %s: %w", dep.key, ctor, err)
+		}
+		return instance, nil
+	}}
+
+	value := reflect.New(paramType)
+	value.Interface().(lazyParam).bind(state)
+	return value.Elem()
+}
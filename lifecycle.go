@@ -3,6 +3,7 @@ package compoapp
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
 
 	"golang.org/x/sync/errgroup"
@@ -30,21 +31,39 @@ type Redier interface {
 type LifecycleRunner struct {
 	container *Container
 	target    any
+	opts      []ResolveOption
+	// functions invoked after construction, before Init
+	setup []any
 	// responsible for logs
 	debug bool
 }
 
 // ResolveLifecycle creates LifecycleRunner from container
-func (c *Container) ResolveLifecycle(target any) *LifecycleRunner {
-	return &LifecycleRunner{container: c, target: target, debug: c.debug}
+func (c *Container) ResolveLifecycle(target any, opts ...ResolveOption) *LifecycleRunner {
+	return &LifecycleRunner{container: c, target: target, opts: opts, debug: c.debug}
+}
+
+// Invoke registers setup steps, e.g. registering routes or running migrations.
+// They are invoked with injected arguments in registration order after construction and before Init.
+func (r *LifecycleRunner) Invoke(fns ...any) *LifecycleRunner {
+	r.setup = append(r.setup, fns...)
+	return r
 }
 
 func (r *LifecycleRunner) Execute(ctx context.Context) error {
-	if err := r.container.Resolve(r.target); err != nil {
+	if err := r.container.ResolveContext(ctx, r.target, r.opts...); err != nil {
 		return fmt.Errorf("resolve: %w", err)
 	}
 
-	for _, component := range r.container.sorted {
+	for _, fn := range r.setup {
+		r.debugf("invoking %T", fn)
+		if err := r.container.invoke(ctx, nil, fn); err != nil {
+			return fmt.Errorf("invoke %T: %w", fn, err)
+		}
+	}
+
+	components := r.pending()
+	for _, component := range components {
 		if i, ok := component.(Initer); ok {
 			r.debugf("calling %T.Init(ctx)", i)
 			if err := i.Init(ctx); err != nil {
@@ -79,7 +98,7 @@ func (r *LifecycleRunner) Execute(ctx context.Context) error {
 	// todo: can be implement in more convenient way?
 	eg, ctx := errgroup.WithContext(ctx)
 
-	for _, component := range r.container.sorted {
+	for _, component := range components {
 		s, ok := component.(Starter)
 		if !ok {
 			continue
@@ -112,6 +131,29 @@ func (r *LifecycleRunner) Execute(ctx context.Context) error {
 	return nil
 }
 
+// pending returns resolved components not launched by an earlier Execute and marks them launched,
+// so components shared between several runs are initialized and started once
+func (r *LifecycleRunner) pending() []any {
+	c := r.container
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	components := make([]any, 0, len(c.sorted))
+	for _, component := range c.sorted {
+		if !reflect.ValueOf(component).Comparable() {
+			components = append(components, component)
+			continue
+		}
+		if c.launched[component] {
+			r.debugf("%T is already launched", component)
+			continue
+		}
+		c.launched[component] = true
+		components = append(components, component)
+	}
+	return components
+}
+
 func (r *LifecycleRunner) debugf(format string, args ...any) {
 	if r.debug {
 		fmtStr := "[LIFECYCLE] " + format + "\n"
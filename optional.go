@@ -0,0 +1,57 @@
+package compoapp
+
+import (
+	"reflect"
+)
+
+// Optional holds a dependency which may be missing from the container.
+//
+// Declare an Optional[T] constructor parameter to receive it. When nothing provides T
+// the constructor gets an empty Optional instead of a resolution error.
+// The `optional:"true"` tag does the same for plain parameters, see ParamTags.
+type Optional[T any] struct {
+	value T
+	ok    bool
+}
+
+// Get returns the dependency and whether it was provided
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// Value returns the dependency or the zero value of T when it was not provided
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+func (Optional[T]) optionalType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (o *Optional[T]) set(value any) {
+	o.value, _ = value.(T)
+	o.ok = true
+}
+
+// optionalParam is implemented by every *Optional[T], so the container can recognize and fill them
+type optionalParam interface {
+	optionalType() reflect.Type
+	set(value any)
+}
+
+var optionalParamType = reflect.TypeFor[optionalParam]()
+
+// optionalElem returns T for an Optional[T] parameter type
+func optionalElem(paramType reflect.Type) (reflect.Type, bool) {
+	if paramType.Kind() != reflect.Struct || !reflect.PointerTo(paramType).Implements(optionalParamType) {
+		return nil, false
+	}
+	return reflect.New(paramType).Interface().(optionalParam).optionalType(), true
+}
+
+// optionalValue creates a provided Optional[T] value of paramType
+func optionalValue(paramType reflect.Type, instance any) reflect.Value {
+	value := reflect.New(paramType)
+	value.Interface().(optionalParam).set(instance)
+	return value.Elem()
+}
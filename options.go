@@ -0,0 +1,182 @@
+package compoapp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ProvideOption configures how a constructor is registered in the container
+type ProvideOption func(*provideOptions)
+
+type provideOptions struct {
+	// name under which the constructor result is registered
+	name string
+	// value group the constructor result is added to
+	group string
+	// how long constructed instances live
+	lifetime Lifetime
+	// tags for constructor parameters, in order
+	paramTags []string
+	// interfaces the constructor results are bound to, as pointers to interfaces
+	as []any
+	// primary results are preferred when several types implement an interface
+	primary bool
+	// replace constructors already providing the results
+	override bool
+}
+
+// Name registers the constructor result under the given name.
+//
+// Named results are distinct nodes, so several constructors may return the same type
+// as long as their names differ.
+func Name(name string) ProvideOption {
+	return func(o *provideOptions) {
+		o.name = name
+	}
+}
+
+// Group adds the constructor result to the named value group.
+//
+// Constructors receive all members of a group as a slice, see ParamTags.
+func Group(name string) ProvideOption {
+	return func(o *provideOptions) {
+		o.group = name
+	}
+}
+
+// WithLifetime sets how long instances built by the constructor live, Singleton by default
+func WithLifetime(lifetime Lifetime) ProvideOption {
+	return func(o *provideOptions) {
+		o.lifetime = lifetime
+	}
+}
+
+// ParamTags annotates constructor parameters in order using struct tag syntax.
+//
+// Supported keys:
+//
+//	name:"replica"   - inject the result registered with Name("replica")
+//	group:"handlers" - inject a slice with every member of the group, in registration order
+//	optional:"true"  - inject the zero value when nothing provides the dependency
+//	cycle:"break"    - leave a Lazy[T] or Provider[T] parameter out of cycle detection, so it may close a cycle
+//
+// Use an empty string to leave a parameter untagged. The same tags apply to fields of In parameter structs.
+func ParamTags(tags ...string) ProvideOption {
+	return func(o *provideOptions) {
+		o.paramTags = tags
+	}
+}
+
+// As binds the constructor result to the given interfaces, passed as pointers, e.g. As(new(IEmailService)).
+//
+// With several results every interface is bound to the single result implementing it.
+func As(ifaces ...any) ProvideOption {
+	return func(o *provideOptions) {
+		o.as = append(o.as, ifaces...)
+	}
+}
+
+// Primary marks the constructor results as the default implementation of interfaces
+// several provided types implement. Bindings take precedence over it.
+func Primary() ProvideOption {
+	return func(o *provideOptions) {
+		o.primary = true
+	}
+}
+
+// Override replaces the constructor already providing the same type, e.g. with a test double.
+//
+// Without it providing a type twice is an error. Types already constructed cannot be overridden.
+func Override() ProvideOption {
+	return func(o *provideOptions) {
+		o.override = true
+	}
+}
+
+// ResolveOption configures which instance Resolve returns
+type ResolveOption func(*resolveOptions)
+
+type resolveOptions struct {
+	name string
+	// build every type, not only those the target needs
+	eager bool
+	// number of singleton constructors run concurrently, see Parallel
+	workers int
+}
+
+// Named resolves the instance registered with Name(name)
+func Named(name string) ResolveOption {
+	return func(o *resolveOptions) {
+		o.name = name
+	}
+}
+
+// Eager builds every provided singleton, not only those the target depends on,
+// so constructor errors of unrelated types surface as well
+func Eager() ResolveOption {
+	return func(o *resolveOptions) {
+		o.eager = true
+	}
+}
+
+// Parallel runs constructors of singletons concurrently once their dependencies are built,
+// at most workers at a time. The first constructor error stops scheduling more of them.
+//
+// Only the constructors run in parallel: arguments are collected, transient dependencies built
+// and results decorated one at a time. Constructors must not rely on the order of unrelated types.
+func Parallel(workers int) ResolveOption {
+	return func(o *resolveOptions) {
+		o.workers = workers
+	}
+}
+
+// applyParamTags attaches tags from ParamTags to the constructor arguments
+func applyParamTags(args []dependency, tags []string, numParams int) error {
+	if len(tags) > numParams {
+		return fmt.Errorf("got %d param tags for %d parameters", len(tags), numParams)
+	}
+
+	for i := range args {
+		dep := &args[i]
+		if dep.param >= len(tags) || tags[dep.param] == "" {
+			continue
+		}
+		if dep.field != nil {
+			return fmt.Errorf("parameter %d is an In struct, tag its fields instead", dep.param)
+		}
+		if err := applyTag(dep, reflect.StructTag(tags[dep.param])); err != nil {
+			return fmt.Errorf("parameter %d: %w", dep.param, err)
+		}
+	}
+
+	return nil
+}
+
+// applyTag parses the tag of a parameter or In struct field into the dependency
+func applyTag(dep *dependency, tag reflect.StructTag) error {
+	dep.key.name = tag.Get("name")
+	dep.group = tag.Get("group")
+	dep.optional = dep.wrapped || tag.Get("optional") == "true"
+	dep.breaksCycle = tag.Get("cycle") == "break"
+
+	if dep.breaksCycle && !dep.lazy {
+		return fmt.Errorf("only lazy dependencies can break a cycle")
+	}
+	if dep.variadic && dep.key.name != "" {
+		return fmt.Errorf("variadic parameter cannot be named, it collects value groups")
+	}
+	if dep.group == "" {
+		return nil
+	}
+	if dep.lazy {
+		return fmt.Errorf("lazy dependencies cannot be grouped")
+	}
+	if dep.key.name != "" {
+		return fmt.Errorf("dependency cannot be both named and grouped")
+	}
+	if dep.key.typ.Kind() != reflect.Slice {
+		return fmt.Errorf("group dependency must be a slice, got %s", dep.key.typ)
+	}
+
+	return nil
+}
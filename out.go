@@ -0,0 +1,68 @@
+package compoapp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Out marks a result struct whose exported fields are each registered as provided types.
+//
+// Embed it into a struct and return the struct from a constructor:
+//
+//	type PoolResult struct {
+//		compoapp.Out
+//
+//		Pool    *Pool
+//		Metrics *Metrics `name:"pool"`
+//		Check   Checker  `group:"health"`
+//	}
+//
+//	func NewPool(cfg *Config) (PoolResult, error)
+//
+// Fields accept the name and group tags.
+type Out struct{}
+
+var outType = reflect.TypeFor[Out]()
+
+// isOut reports whether the result type is a struct embedding Out
+func isOut(resultType reflect.Type) bool {
+	if resultType.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < resultType.NumField(); i++ {
+		field := resultType.Field(i)
+		if field.Anonymous && field.Type == outType {
+			return true
+		}
+	}
+	return false
+}
+
+// outFields returns a result for every field of the Out result struct
+func outFields(resultType reflect.Type, out int) ([]result, error) {
+	results := make([]result, 0, resultType.NumField())
+	for i := 0; i < resultType.NumField(); i++ {
+		field := resultType.Field(i)
+		if field.Anonymous && field.Type == outType {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("field %s of %s must be exported to be provided", field.Name, resultType)
+		}
+		if err := checkResultType(field.Type); err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", field.Name, resultType, err)
+		}
+
+		r := result{
+			key:   key{typ: field.Type, name: field.Tag.Get("name")},
+			out:   out,
+			field: field.Index,
+			group: field.Tag.Get("group"),
+		}
+		if r.key.name != "" && r.group != "" {
+			return nil, fmt.Errorf("field %s of %s cannot be both named and grouped", field.Name, resultType)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,148 @@
+package compoapp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// build calls the constructors of the pending singletons, given in sorted order by one of their keys.
+// With more than one worker, constructors whose dependencies are built run concurrently.
+func (c *Container) build(pending []key, workers int) error {
+	if workers <= 1 || len(pending) <= 1 {
+		for _, k := range pending {
+			if err := c.resolutionContext().Err(); err != nil {
+				return fmt.Errorf("resolution aborted before %s: %w", k, err)
+			}
+			if err := c.resolveInstance(c.typesCtors[k]); err != nil {
+				return fmt.Errorf("failed to resolve %s: %w", k, err)
+			}
+		}
+		return nil
+	}
+
+	c.debugf("building %d singletons with %d workers", len(pending), workers)
+	waits, dependents := c.buildOrder(pending)
+
+	eg, ctx := errgroup.WithContext(c.resolutionContext())
+	eg.SetLimit(workers)
+	// guards the container while constructors run, only the constructor calls overlap
+	var mu sync.Mutex
+	// buffered, so workers never wait for the scheduler
+	done := make(chan key, len(pending))
+
+	start := func(k key) {
+		if ctx.Err() != nil {
+			return
+		}
+		eg.Go(func() error {
+			if err := c.buildConcurrently(&mu, c.typesCtors[k]); err != nil {
+				return fmt.Errorf("failed to resolve %s: %w", k, err)
+			}
+			done <- k
+			return nil
+		})
+	}
+
+	for _, k := range pending {
+		if waits[k] == 0 {
+			start(k)
+		}
+	}
+	for finished := 0; finished < len(pending); finished++ {
+		select {
+		case k := <-done:
+			c.debugf("%s is built", k)
+			for _, dependent := range dependents[k] {
+				waits[dependent]--
+				if waits[dependent] == 0 {
+					start(dependent)
+				}
+			}
+		case <-ctx.Done():
+			if err := eg.Wait(); err != nil {
+				return err
+			}
+			// the resolution context is done while no constructor was running
+			return fmt.Errorf("resolution aborted: %w", context.Cause(ctx))
+		}
+	}
+	return eg.Wait()
+}
+
+// buildOrder counts for every pending singleton the pending singletons it needs, directly or through
+// types built on demand, e.g. transient ones. dependents lists the pending singletons waiting for each one.
+func (c *Container) buildOrder(pending []key) (waits map[key]int, dependents map[key][]key) {
+	byCtor := make(map[*constructorInfo]key, len(pending))
+	for _, k := range pending {
+		byCtor[c.typesCtors[k]] = k
+	}
+
+	waits = make(map[key]int, len(pending))
+	dependents = make(map[key][]key)
+	for _, k := range pending {
+		ctor := c.typesCtors[k]
+		needs := make(map[key]bool)
+		visited := make(map[key]bool)
+
+		var visit func(node key)
+		visit = func(node key) {
+			for _, dep := range c.graph.dependencies[node] {
+				if visited[dep] {
+					continue
+				}
+				visited[dep] = true
+
+				depCtor, exists := c.typesCtors[dep]
+				if !exists || depCtor == ctor {
+					continue
+				}
+				if needed, ok := byCtor[depCtor]; ok {
+					needs[needed] = true
+					continue
+				}
+				if _, built := c.instances[dep]; !built {
+					visit(dep)
+				}
+			}
+		}
+		for _, r := range ctor.signature.results {
+			if c.typesCtors[r.key] == ctor {
+				visit(r.key)
+			}
+		}
+
+		waits[k] = len(needs)
+		for needed := range needs {
+			dependents[needed] = append(dependents[needed], k)
+		}
+	}
+	return waits, dependents
+}
+
+// buildConcurrently is resolveInstance with mu held everywhere except around the constructor call
+func (c *Container) buildConcurrently(mu *sync.Mutex, ctor *constructorInfo) error {
+	mu.Lock()
+	args, err := c.constructorArgs(ctor, nil, -1, reflect.Value{})
+	mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	results, err := c.call(ctor, args)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err != nil {
+		return constructorError(ctor, ctor.node(), err)
+	}
+	instances, err := c.decorateResults(ctor, results, nil)
+	if err != nil {
+		return err
+	}
+	c.storeInstances(ctor, instances)
+	return nil
+}
@@ -0,0 +1,81 @@
+package compoapp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Provider builds a new T on every call using the constructor registered for T.
+//
+// Declare a Provider[T] or a plain func() (T, error) constructor parameter to receive it.
+// Dependencies of T are resolved as usual, so singletons are shared between calls.
+// Like Lazy, a provider must not be called from a constructor.
+type Provider[T any] func() (T, error)
+
+func (Provider[T]) providerType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// providerParam is implemented by every Provider[T]
+type providerParam interface {
+	providerType() reflect.Type
+}
+
+var (
+	providerParamType = reflect.TypeFor[providerParam]()
+	errorType         = reflect.TypeFor[error]()
+)
+
+// providerElem returns T for a Provider[T] or func() (T, error) parameter type
+func providerElem(paramType reflect.Type) (reflect.Type, bool) {
+	if paramType.Kind() != reflect.Func {
+		return nil, false
+	}
+	if paramType.Implements(providerParamType) {
+		return reflect.Zero(paramType).Interface().(providerParam).providerType(), true
+	}
+	if paramType.NumIn() == 0 && paramType.NumOut() == 2 && paramType.Out(1) == errorType {
+		return paramType.Out(0), true
+	}
+	return nil, false
+}
+
+// providerValue creates a factory of paramType which constructs a new dependency on every call
+func (c *Container) providerValue(paramType reflect.Type, dep dependency, scope *Scope) reflect.Value {
+	elemType := paramType.Out(0)
+
+	return reflect.MakeFunc(paramType, func([]reflect.Value) []reflect.Value {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		instance, err := c.provide(dep.key, scope)
+		if err != nil {
+			return []reflect.Value{reflect.Zero(elemType), reflect.ValueOf(&err).Elem()}
+		}
+		if instance == nil {
+			return []reflect.Value{reflect.Zero(elemType), reflect.Zero(errorType)}
+		}
+		return []reflect.Value{reflect.ValueOf(instance), reflect.Zero(errorType)}
+	})
+}
+
+// provide builds a new instance for the key regardless of its lifetime
+func (c *Container) provide(k key, scope *Scope) (any, error) {
+	ctor, exists := c.typesCtors[k]
+	if !exists {
+		return nil, fmt.Errorf("no constructor registered for %s", k)
+	}
+	if scope != nil && scope.closed {
+		return nil, fmt.Errorf("scope is closed")
+	}
+
+	c.debugf("providing new %s", k)
+	instances, err := c.construct(ctor, scope)
+	if err != nil {
+		return nil, err
+	}
+	if scope != nil {
+		scope.created = append(scope.created, uniqueInstances(instances)...)
+	}
+	return instances[ctor.resultIndex(k)], nil
+}
@@ -289,7 +289,8 @@ func main() {
 	container.MustProvide(NewHTTPServer)
 
 	var server *HTTPServer
-	if err := container.ResolveLifecycle(&server).Execute(ctx); err != nil {
+	// MetricsCollector is not a dependency of the server, Eager builds and starts it too
+	if err := container.ResolveLifecycle(&server, compoapp.Eager()).Execute(ctx); err != nil {
 		fmt.Println("fatal:", err)
 		os.Exit(1)
 	}
@@ -291,19 +291,24 @@ type UserService struct {
 	eventBus     *EventBus
 }
 
-func NewUserService(
-	repo IUserRepository,
-	emailService IEmailService,
-	logger *Logger,
-	metrics *Metrics,
-	eventBus *EventBus,
-) *UserService {
+// UserServiceParams groups UserService dependencies, each field is injected by the container
+type UserServiceParams struct {
+	compoapp.In
+
+	Repo         IUserRepository
+	EmailService IEmailService
+	Logger       *Logger
+	Metrics      *Metrics
+	EventBus     *EventBus
+}
+
+func NewUserService(p UserServiceParams) *UserService {
 	return &UserService{
-		repo:         repo,
-		emailService: emailService,
-		logger:       logger,
-		metrics:      metrics,
-		eventBus:     eventBus,
+		repo:         p.Repo,
+		emailService: p.EmailService,
+		logger:       p.Logger,
+		metrics:      p.Metrics,
+		eventBus:     p.EventBus,
 	}
 }
 
@@ -0,0 +1,98 @@
+package compoapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Lifetime describes how long instances built by a constructor live
+type Lifetime int
+
+const (
+	// Singleton instances are built once per container
+	Singleton Lifetime = iota
+	// Scoped instances are built once per Scope, e.g. per request
+	Scoped
+	// Transient instances are built for every injection
+	Transient
+)
+
+func (l Lifetime) String() string {
+	switch l {
+	case Singleton:
+		return "singleton"
+	case Scoped:
+		return "scoped"
+	case Transient:
+		return "transient"
+	default:
+		return fmt.Sprintf("Lifetime(%d)", int(l))
+	}
+}
+
+// Scope is a child of the container which owns scoped instances.
+//
+// Singletons are shared with the parent container, scoped instances live until Close.
+type Scope struct {
+	container *Container
+	instances map[key]any
+	// instances created by the scope in construction order, disposed in reverse
+	created []any
+	closed  bool
+}
+
+// NewScope creates a child scope, e.g. for a single request
+func (c *Container) NewScope() *Scope {
+	return &Scope{
+		container: c,
+		instances: make(map[key]any),
+	}
+}
+
+// Resolve resolves the target like Container.Resolve, building scoped instances in this scope
+func (s *Scope) Resolve(target any, opts ...ResolveOption) error {
+	return s.ResolveContext(context.Background(), target, opts...)
+}
+
+// MustResolve is like Resolve but panics on error.
+func (s *Scope) MustResolve(target any, opts ...ResolveOption) {
+	if err := s.Resolve(target, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// Invoke calls fn like Container.Invoke, building scoped dependencies in this scope
+func (s *Scope) Invoke(fn any) error {
+	return s.container.invoke(context.Background(), s, fn)
+}
+
+// Close disposes instances created by the scope in reverse construction order.
+// Instances implementing io.Closer are closed, all errors are returned joined.
+func (s *Scope) Close() error {
+	c := s.container
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var errs []error
+	for i := len(s.created) - 1; i >= 0; i-- {
+		closer, ok := s.created[i].(io.Closer)
+		if !ok {
+			continue
+		}
+		c.debugf("closing %T", closer)
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %T: %w", closer, err))
+		}
+	}
+	s.instances = nil
+	s.created = nil
+
+	return errors.Join(errs...)
+}
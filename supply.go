@@ -0,0 +1,73 @@
+package compoapp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MustSupply registers pre-built values and panics on error
+func (c *Container) MustSupply(values ...any) {
+	if err := c.Supply(values...); err != nil {
+		panic(err)
+	}
+}
+
+// Supply registers pre-built values, e.g. a config parsed from flags.
+//
+// Supplied values are resolved singletons without dependencies: they are injected like
+// constructor results and take part in interface resolution. On error none of them is registered.
+func (c *Container) Supply(values ...any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	location := callerLocation()
+	// every value is checked before any is registered
+	supplied := make(map[key]bool, len(values))
+	for _, value := range values {
+		if value == nil {
+			return fmt.Errorf("cannot supply untyped nil")
+		}
+
+		valueType := reflect.TypeOf(value)
+		if err := checkResultType(valueType); err != nil {
+			return fmt.Errorf("failed to supply %s: %w", valueType, err)
+		}
+
+		k := key{typ: valueType}
+		if supplied[k] {
+			return fmt.Errorf("%s is supplied more than once at %s", k, location)
+		}
+		supplied[k] = true
+		if err := c.checkDuplicate(k, false, location); err != nil {
+			return err
+		}
+	}
+
+	for _, value := range values {
+		valueType := reflect.TypeOf(value)
+		// constructor returning the value keeps supplied nodes indistinguishable from provided ones
+		fnType := reflect.FuncOf(nil, []reflect.Type{valueType}, false)
+		fn := reflect.MakeFunc(fnType, func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.ValueOf(value)}
+		})
+
+		k := key{typ: valueType}
+		if _, exists := c.typesCtors[k]; exists {
+			c.unregister(k)
+		}
+
+		cinfo := &constructorInfo{
+			fn:        fn.Interface(),
+			name:      "supplied " + valueType.String(),
+			signature: fnSignature{results: []result{{key: k}}},
+			location:  location,
+		}
+		c.debugf("supplied %s", valueType)
+
+		c.constructors = append(c.constructors, cinfo)
+		c.typesCtors[k] = cinfo
+		c.typeRegistry = append(c.typeRegistry, k)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package compoapp
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Template is a generic constructor registered once for several type arguments.
+// Go cannot instantiate generic functions at runtime, so every instantiation is added explicitly:
+//
+//	var Repositories = compoapp.NewTemplate(compoapp.WithLifetime(compoapp.Scoped))
+//
+//	Repositories.Instantiate(NewRepository[User], NewRepository[Order])
+//	container.MustProvideTemplate(Repositories)
+//
+// Each instantiation provides its own types and is a separate node of the graph.
+type Template struct {
+	mu           sync.Mutex
+	opts         []ProvideOption
+	constructors []instantiation
+}
+
+type instantiation struct {
+	constructor any
+	location    string
+}
+
+// NewTemplate creates an empty template, the options apply to every instantiation
+func NewTemplate(opts ...ProvideOption) *Template {
+	return &Template{opts: opts}
+}
+
+// Instantiate adds instantiations of the generic constructor, e.g. NewRepository[User]
+func (t *Template) Instantiate(constructors ...any) *Template {
+	location := callerLocation()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, constructor := range constructors {
+		t.constructors = append(t.constructors, instantiation{constructor: constructor, location: location})
+	}
+	return t
+}
+
+// MustProvideTemplate registers every instantiation of the template and panics on error
+func (c *Container) MustProvideTemplate(t *Template) {
+	if err := c.ProvideTemplate(t); err != nil {
+		panic(err)
+	}
+}
+
+// ProvideTemplate registers every instantiation of the template.
+// Instantiations must come from the same generic function. On error none of them is registered.
+func (c *Container) ProvideTemplate(t *Template) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var template string
+	for _, inst := range t.constructors {
+		name, err := genericName(inst.constructor)
+		if err != nil {
+			return fmt.Errorf("failed to provide template instantiated at %s: %w", inst.location, err)
+		}
+		if template == "" {
+			template = name
+		}
+		if name != template {
+			return fmt.Errorf("%s instantiated at %s is not an instantiation of %s", name, inst.location, template)
+		}
+	}
+
+	// registrations of earlier instantiations are rolled back when a later one fails
+	constructors, typesCtors, typeRegistry := slices.Clone(c.constructors), maps.Clone(c.typesCtors), slices.Clone(c.typeRegistry)
+	groups, bindings := maps.Clone(c.groups), maps.Clone(c.bindings)
+	for _, inst := range t.constructors {
+		if err := c.register(inst.constructor, inst.location, template, t.opts...); err != nil {
+			c.constructors, c.typesCtors, c.typeRegistry = constructors, typesCtors, typeRegistry
+			c.groups, c.bindings = groups, bindings
+			return err
+		}
+	}
+	return nil
+}
+
+// genericName returns the name of the generic function the constructor instantiates, e.g. pkg.NewRepository[...]
+func genericName(constructor any) (string, error) {
+	value := reflect.ValueOf(constructor)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return "", fmt.Errorf("constructor must be a function")
+	}
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil || !strings.HasSuffix(fn.Name(), "[...]") {
+		return "", fmt.Errorf("%s is not an instantiation of a generic function", value.Type())
+	}
+	// the package path is dropped like in type names
+	return fn.Name()[strings.LastIndex(fn.Name(), "/")+1:], nil
+}
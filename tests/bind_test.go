@@ -0,0 +1,127 @@
+package compoapp_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type IEmailService interface {
+	Send(to string) error
+}
+
+type SMTPEmailService struct{}
+
+func (s *SMTPEmailService) Send(string) error { return nil }
+
+type SESEmailService struct{}
+
+func (s *SESEmailService) Send(string) error { return nil }
+
+type Notifier struct {
+	email IEmailService
+}
+
+func NewNotifier(email IEmailService) *Notifier {
+	return &Notifier{email: email}
+}
+
+var _ = Describe("Interface bindings", func() {
+	var container *compoapp.Container
+
+	newSMTP := func() *SMTPEmailService { return &SMTPEmailService{} }
+	newSES := func() *SESEmailService { return &SESEmailService{} }
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should fail on ambiguous implementations", func() {
+		Expect(container.Provide(newSMTP)).To(Succeed())
+		Expect(container.Provide(newSES)).To(Succeed())
+		Expect(container.Provide(NewNotifier)).To(Succeed())
+
+		var notifier *Notifier
+		Expect(container.Resolve(&notifier)).To(MatchError(ContainSubstring("use Bind or Primary to choose one")))
+	})
+
+	It("should inject the bound implementation", func() {
+		Expect(container.Provide(newSMTP)).To(Succeed())
+		Expect(container.Provide(newSES)).To(Succeed())
+		Expect(container.Provide(NewNotifier)).To(Succeed())
+		Expect(compoapp.Bind[IEmailService, *SMTPEmailService](container)).To(Succeed())
+
+		var notifier *Notifier
+		Expect(container.Resolve(&notifier)).To(Succeed())
+		Expect(notifier.email).To(BeAssignableToTypeOf(&SMTPEmailService{}))
+
+		var smtp *SMTPEmailService
+		Expect(container.Resolve(&smtp)).To(Succeed())
+		Expect(notifier.email).To(BeIdenticalTo(smtp))
+	})
+
+	It("should bind constructor results with As", func() {
+		Expect(container.Provide(newSMTP)).To(Succeed())
+		Expect(container.Provide(newSES, compoapp.As(new(IEmailService)))).To(Succeed())
+		Expect(container.Provide(NewNotifier)).To(Succeed())
+
+		var notifier *Notifier
+		Expect(container.Resolve(&notifier)).To(Succeed())
+		Expect(notifier.email).To(BeAssignableToTypeOf(&SESEmailService{}))
+	})
+
+	It("should prefer the primary implementation", func() {
+		Expect(container.Provide(newSMTP)).To(Succeed())
+		Expect(container.Provide(newSES, compoapp.Primary())).To(Succeed())
+		Expect(container.Provide(NewNotifier)).To(Succeed())
+
+		var notifier *Notifier
+		Expect(container.Resolve(&notifier)).To(Succeed())
+		Expect(notifier.email).To(BeAssignableToTypeOf(&SESEmailService{}))
+	})
+
+	It("should validate bindings at bind time", func() {
+		Expect(compoapp.Bind[IEmailService, *Database](container)).To(
+			MatchError(ContainSubstring("does not implement the interface")))
+		Expect(compoapp.Bind[*Database, *Database](container)).To(
+			MatchError(ContainSubstring("not an interface")))
+		Expect(container.Provide(NewDatabase, compoapp.As(new(IEmailService)))).To(
+			MatchError(ContainSubstring("must match exactly one result")))
+		Expect(container.Provide(NewDatabase, compoapp.As(IEmailService(nil)))).To(
+			MatchError(ContainSubstring("As expects pointers to interfaces")))
+
+		compoapp.MustBind[IEmailService, *SMTPEmailService](container)
+		Expect(compoapp.Bind[IEmailService, *SESEmailService](container)).To(
+			MatchError(ContainSubstring("already bound to *compoapp_test.SMTPEmailService")))
+	})
+
+	It("should fail when the bound implementation is not provided", func() {
+		Expect(container.Provide(newSES)).To(Succeed())
+		Expect(container.Provide(NewNotifier)).To(Succeed())
+		compoapp.MustBind[IEmailService, *SMTPEmailService](container)
+
+		var notifier *Notifier
+		Expect(container.Resolve(&notifier)).To(MatchError(ContainSubstring("which is not provided")))
+	})
+
+	It("should draw bindings in bold", func() {
+		Expect(container.Provide(newSMTP)).To(Succeed())
+		Expect(container.Provide(newSES)).To(Succeed())
+		Expect(container.Provide(NewNotifier)).To(Succeed())
+		compoapp.MustBind[IEmailService, *SMTPEmailService](container)
+
+		var notifier *Notifier
+		Expect(container.Resolve(&notifier)).To(Succeed())
+
+		path := filepath.Join(GinkgoT().TempDir(), "graph.dot")
+		Expect(container.Visualize(path)).To(Succeed())
+		dot, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(dot)).To(ContainSubstring(`"*compoapp_test.Notifier" -> "compoapp_test.IEmailService";`))
+		Expect(string(dot)).To(ContainSubstring(`"compoapp_test.IEmailService" -> "*compoapp_test.SMTPEmailService" [style=bold];`))
+	})
+})
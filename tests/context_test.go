@@ -0,0 +1,153 @@
+package compoapp_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type requestIDKey struct{}
+
+var _ = Describe("ResolveContext", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should inject the context into constructors", func() {
+		Expect(container.Provide(func(ctx context.Context) *Database {
+			return &Database{Host: ctx.Value(requestIDKey{}).(string)}
+		})).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "db:5432")
+		var auth *AuthService
+		Expect(container.ResolveContext(ctx, &auth)).To(Succeed())
+		Expect(auth.db.Host).To(Equal("db:5432"))
+	})
+
+	It("should inject a background context without ResolveContext", func() {
+		var got context.Context
+		Expect(container.Provide(func(ctx context.Context) *Database {
+			got = ctx
+			return &Database{}
+		})).To(Succeed())
+
+		var db *Database
+		Expect(container.Resolve(&db)).To(Succeed())
+		Expect(got).To(Equal(context.Background()))
+	})
+
+	It("should name the constructor running when the deadline hits", func() {
+		release := make(chan struct{})
+		DeferCleanup(func() { close(release) })
+
+		authCalls := 0
+		Expect(container.Provide(func() *Database {
+			<-release
+			return &Database{}
+		})).To(Succeed())
+		Expect(container.Provide(func(db *Database) *AuthService {
+			authCalls++
+			return &AuthService{db: db}
+		})).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		var auth *AuthService
+		err := container.ResolveContext(ctx, &auth)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+
+		var ctorErr *compoapp.ConstructorError
+		Expect(errors.As(err, &ctorErr)).To(BeTrue())
+		Expect(ctorErr.Constructor).To(Equal("func() *compoapp_test.Database"))
+		Expect(authCalls).To(Equal(0))
+	})
+
+	It("should not call constructors once the context is done", func() {
+		calls := 0
+		Expect(container.Provide(func() *Database {
+			calls++
+			return &Database{}
+		})).To(Succeed())
+		Expect(container.Provide(func() *Cache {
+			calls++
+			return &Cache{}
+		})).To(Succeed())
+		Expect(container.Provide(NewUserService)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var users *UserService
+		err := container.ResolveContext(ctx, &users)
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(err).To(MatchError(ContainSubstring("resolution aborted before *compoapp_test.Cache")))
+		Expect(calls).To(Equal(0))
+	})
+
+	It("should abort between constructors", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		dbCalls := 0
+		// types are built in a stable order, the cache first
+		Expect(container.Provide(func() *Cache {
+			cancel()
+			return &Cache{}
+		})).To(Succeed())
+		Expect(container.Provide(func() *Database {
+			dbCalls++
+			return &Database{}
+		})).To(Succeed())
+		Expect(container.Provide(NewUserService)).To(Succeed())
+
+		var users *UserService
+		Expect(container.ResolveContext(ctx, &users)).To(MatchError(context.Canceled))
+		Expect(dbCalls).To(Equal(0))
+	})
+
+	It("should abort parallel construction", func() {
+		release := make(chan struct{})
+		DeferCleanup(func() { close(release) })
+
+		Expect(container.Provide(func() *Database {
+			<-release
+			return &Database{}
+		})).To(Succeed())
+		Expect(container.Provide(NewCache)).To(Succeed())
+		Expect(container.Provide(NewUserService)).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		var users *UserService
+		err := container.ResolveContext(ctx, &users, compoapp.Parallel(2))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(err).To(MatchError(ContainSubstring("constructor func() *compoapp_test.Database")))
+	})
+
+	It("should resolve scoped instances with the context", func() {
+		Expect(container.Provide(func(ctx context.Context) *Cache {
+			return &Cache{Host: ctx.Value(requestIDKey{}).(string)}
+		}, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+
+		scope := container.NewScope()
+		defer scope.Close()
+
+		var cache *Cache
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "request-1")
+		Expect(scope.ResolveContext(ctx, &cache)).To(Succeed())
+		Expect(cache.Host).To(Equal("request-1"))
+	})
+
+	It("should reject providing contexts", func() {
+		Expect(container.Provide(func() context.Context { return context.Background() })).To(
+			MatchError(ContainSubstring("is passed by ResolveContext and cannot be provided")))
+	})
+})
@@ -0,0 +1,75 @@
+package compoapp_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+var _ = Describe("Cycles", func() {
+	type A struct{}
+	type B struct{}
+	type C struct{}
+	type D struct{}
+	type E struct{}
+	type Root struct{}
+
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should report every independent cycle in order", func() {
+		// A -> B -> C -> A and D -> D, E depends on the first cycle without being part of it
+		Expect(container.Provide(func(*B) *A { return &A{} })).To(Succeed())
+		Expect(container.Provide(func(*C) *B { return &B{} })).To(Succeed())
+		Expect(container.Provide(func(*A) *C { return &C{} })).To(Succeed())
+		Expect(container.Provide(func(*D) *D { return &D{} })).To(Succeed())
+		Expect(container.Provide(func(*A) *E { return &E{} })).To(Succeed())
+		Expect(container.Provide(func(*E, *D) *Root { return &Root{} })).To(Succeed())
+
+		var root *Root
+		err := container.Resolve(&root)
+
+		var cycleErr *compoapp.CycleError
+		Expect(errors.As(err, &cycleErr)).To(BeTrue())
+		Expect(cycleErr.Cycles).To(HaveLen(2))
+		Expect(cycleErr.Cycles[0].Types).To(Equal([]string{"*compoapp_test.A", "*compoapp_test.B", "*compoapp_test.C"}))
+		Expect(cycleErr.Cycles[0].Constructors).To(Equal([]string{
+			"func(*compoapp_test.B) *compoapp_test.A",
+			"func(*compoapp_test.C) *compoapp_test.B",
+			"func(*compoapp_test.A) *compoapp_test.C",
+		}))
+		Expect(cycleErr.Cycles[1].Types).To(Equal([]string{"*compoapp_test.D"}))
+		Expect(cycleErr.Path).To(Equal([]string{"*compoapp_test.Root", "*compoapp_test.E", "*compoapp_test.A"}))
+
+		Expect(err).To(MatchError(ContainSubstring(
+			"*compoapp_test.C (func(*compoapp_test.A) *compoapp_test.C) -> *compoapp_test.A")))
+		Expect(err).To(MatchError(ContainSubstring(
+			"; *compoapp_test.D (func(*compoapp_test.D) *compoapp_test.D) -> *compoapp_test.D")))
+	})
+
+	It("should render cycles as DOT and Mermaid", func() {
+		Expect(container.Provide(func(*B) *A { return &A{} })).To(Succeed())
+		Expect(container.Provide(func(*A) *B { return &B{} })).To(Succeed())
+
+		var a *A
+		var cycleErr *compoapp.CycleError
+		Expect(errors.As(container.Resolve(&a), &cycleErr)).To(BeTrue())
+
+		Expect(cycleErr.DOT()).To(ContainSubstring(`"*compoapp_test.A" -> "*compoapp_test.B" [color=red];`))
+		Expect(cycleErr.DOT()).To(ContainSubstring(`"*compoapp_test.B" -> "*compoapp_test.A" [color=red];`))
+		Expect(cycleErr.Mermaid()).To(Equal(`flowchart LR
+    n0["*compoapp_test.A"]
+    n1["*compoapp_test.B"]
+    n0 --> n1
+    n1 --> n0
+    classDef cycle stroke:#f00,stroke-width:2px;
+    class n0,n1 cycle;
+`))
+	})
+})
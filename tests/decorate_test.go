@@ -0,0 +1,129 @@
+package compoapp_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type Metrics struct {
+	saves int
+}
+
+type MeteredStorage struct {
+	Storage
+	metrics *Metrics
+	layer   string
+}
+
+func (m *MeteredStorage) Save(data string) error {
+	m.metrics.saves++
+	return m.Storage.Save(data)
+}
+
+var _ = Describe("Decorate", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should inject decorated interfaces", func() {
+		metrics := &Metrics{}
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Supply(metrics)).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+		Expect(container.Decorate(func(s Storage, m *Metrics) Storage {
+			return &MeteredStorage{Storage: s, metrics: m}
+		})).To(Succeed())
+
+		var processor *DataProcessor
+		Expect(container.Resolve(&processor)).To(Succeed())
+		Expect(processor.storage.Save("data")).To(Succeed())
+		Expect(metrics.saves).To(Equal(1))
+	})
+
+	It("should chain decorators in registration order", func() {
+		decorator := func(layer string) func(Storage, *Metrics) Storage {
+			return func(s Storage, m *Metrics) Storage {
+				return &MeteredStorage{Storage: s, metrics: m, layer: layer}
+			}
+		}
+
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(func() *Metrics { return &Metrics{} })).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+		container.MustDecorate(decorator("inner"))
+		container.MustDecorate(decorator("outer"))
+
+		var processor *DataProcessor
+		Expect(container.Resolve(&processor)).To(Succeed())
+		outer := processor.storage.(*MeteredStorage)
+		Expect(outer.layer).To(Equal("outer"))
+		Expect(outer.Storage.(*MeteredStorage).layer).To(Equal("inner"))
+		Expect(outer.Storage.(*MeteredStorage).Storage).To(BeAssignableToTypeOf(&FileStorage{}))
+	})
+
+	It("should decorate named pointer types before dependents are constructed", func() {
+		Expect(container.Provide(func() *Database { return &Database{Host: "primary"} }, compoapp.Name("primary"))).To(Succeed())
+		Expect(container.Decorate(func(db *Database) *Database {
+			return &Database{Host: db.Host + "+traced"}
+		}, compoapp.Name("primary"))).To(Succeed())
+
+		var db *Database
+		Expect(container.Resolve(&db, compoapp.Named("primary"))).To(Succeed())
+		Expect(db.Host).To(Equal("primary+traced"))
+	})
+
+	It("should return decorator errors", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Decorate(func(*Database) (*Database, error) {
+			return nil, errors.New("decorator failed")
+		})).To(Succeed())
+
+		var db *Database
+		err := container.Resolve(&db)
+		Expect(err).To(MatchError(ContainSubstring("decorator 1 of *compoapp_test.Database")))
+		Expect(err).To(MatchError(ContainSubstring("decorator failed")))
+	})
+
+	It("should reject invalid decorators", func() {
+		Expect(container.Decorate("not a function")).To(MatchError("decorator must be a function"))
+		Expect(container.Decorate(func(*Cache) *Database { return nil })).To(
+			MatchError("decorator must accept the decorated *compoapp_test.Database"))
+		Expect(container.Decorate(func(db *Database) *Database { return db }, compoapp.Group("dbs"))).To(
+			MatchError("value groups cannot be decorated"))
+	})
+
+	It("should fail when nothing provides the decorated type", func() {
+		Expect(container.Provide(NewCache)).To(Succeed())
+		Expect(container.Decorate(func(db *Database) *Database { return db })).To(Succeed())
+
+		var cache *Cache
+		Expect(container.Resolve(&cache)).To(MatchError(ContainSubstring("nothing provides decorated *compoapp_test.Database")))
+	})
+
+	It("should draw decorator layers", func() {
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(func() *Metrics { return &Metrics{} })).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+		Expect(container.Decorate(func(s Storage, m *Metrics) Storage { return s })).To(Succeed())
+
+		var processor *DataProcessor
+		Expect(container.Resolve(&processor)).To(Succeed())
+
+		path := filepath.Join(GinkgoT().TempDir(), "graph.dot")
+		Expect(container.Visualize(path)).To(Succeed())
+		dot, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(dot)).To(ContainSubstring(`"compoapp_test.Storage" -> "compoapp_test.Storage (decorator 1)";`))
+		Expect(string(dot)).To(ContainSubstring(`"compoapp_test.Storage (decorator 1)" -> "*compoapp_test.Metrics";`))
+		Expect(string(dot)).To(ContainSubstring(`"compoapp_test.Storage" -> "*compoapp_test.FileStorage";`))
+		Expect(string(dot)).ToNot(ContainSubstring(`"compoapp_test.Storage" -> "*compoapp_test.Metrics";`))
+	})
+})
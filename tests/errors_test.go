@@ -0,0 +1,129 @@
+package compoapp_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+var _ = Describe("Errors", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should report every missing dependency with its path", func() {
+		location := nextLine()
+		Expect(container.Provide(NewUserService)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+		Expect(container.Provide(NewServer)).To(Succeed())
+
+		var server *Server
+		err := container.Resolve(&server)
+
+		var missing *compoapp.MissingDependencyError
+		Expect(errors.As(err, &missing)).To(BeTrue())
+		Expect(missing.Constructor).To(Equal("compoapp_test_test.NewUserService"))
+		Expect(missing.Location).To(Equal(location))
+		Expect(missing.Path).To(Equal([]string{"*compoapp_test.Server", "*compoapp_test.UserService", "*compoapp_test.Database"}))
+
+		Expect(err).To(MatchError(ContainSubstring("path: *compoapp_test.Server -> *compoapp_test.UserService -> *compoapp_test.Cache")))
+		Expect(err).To(MatchError(ContainSubstring("path: *compoapp_test.Server -> *compoapp_test.AuthService -> *compoapp_test.Database")))
+		Expect(err).To(MatchError(ContainSubstring("*compoapp_test.Config, required by")))
+	})
+
+	It("should report missing targets", func() {
+		var db *Database
+		err := container.Resolve(&db)
+
+		var missing *compoapp.MissingDependencyError
+		Expect(errors.As(err, &missing)).To(BeTrue())
+		Expect(missing.Dependency).To(Equal("*compoapp_test.Database"))
+		Expect(missing.Path).To(Equal([]string{"*compoapp_test.Database"}))
+	})
+
+	It("should report cycles", func() {
+		type A struct{}
+		type B struct{}
+		Expect(container.Provide(func(*B) *A { return &A{} })).To(Succeed())
+		Expect(container.Provide(func(*A) *B { return &B{} })).To(Succeed())
+
+		var a *A
+		var cycle *compoapp.CycleError
+		Expect(errors.As(container.Resolve(&a), &cycle)).To(BeTrue())
+		Expect(cycle.Cycles).To(HaveLen(1))
+		Expect(cycle.Cycles[0].Types).To(Equal([]string{"*compoapp_test.A", "*compoapp_test.B"}))
+	})
+
+	It("should report ambiguous bindings", func() {
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(func() *MeteredStorage { return &MeteredStorage{} })).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+
+		var processor *DataProcessor
+		var ambiguous *compoapp.AmbiguousBindingError
+		Expect(errors.As(container.Resolve(&processor), &ambiguous)).To(BeTrue())
+		Expect(ambiguous.Interface).To(Equal("compoapp_test.Storage"))
+		Expect(ambiguous.Implementations).To(ConsistOf("*compoapp_test.FileStorage", "*compoapp_test.MeteredStorage"))
+		Expect(ambiguous.Path).To(Equal([]string{"*compoapp_test.DataProcessor", "compoapp_test.Storage"}))
+	})
+
+	It("should wrap constructor errors", func() {
+		location := nextLine()
+		Expect(container.Provide(NewErrorDatabase)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		var auth *AuthService
+		err := container.Resolve(&auth)
+
+		var ctorErr *compoapp.ConstructorError
+		Expect(errors.As(err, &ctorErr)).To(BeTrue())
+		Expect(ctorErr.Constructor).To(Equal("compoapp_test_test.NewErrorDatabase"))
+		Expect(ctorErr.Location).To(Equal(location))
+		Expect(ctorErr.Path).To(Equal([]string{"*compoapp_test.AuthService", "*compoapp_test.Database"}))
+		Expect(ctorErr.Err).To(MatchError("database connection failed"))
+	})
+
+	It("should name the failing constructor, its signature only for function literals", func() {
+		Expect(container.Provide(NewErrorDatabase)).To(Succeed())
+		Expect(container.Provide(func() (*Cache, error) { return nil, errors.New("cache unavailable") })).To(Succeed())
+
+		var ctorErr *compoapp.ConstructorError
+		var db *Database
+		Expect(errors.As(container.Resolve(&db), &ctorErr)).To(BeTrue())
+		Expect(ctorErr.Constructor).To(Equal("compoapp_test_test.NewErrorDatabase"))
+		var cache *Cache
+		Expect(errors.As(container.Resolve(&cache), &ctorErr)).To(BeTrue())
+		Expect(ctorErr.Constructor).To(Equal("func() (*compoapp_test.Cache, error)"))
+	})
+
+	It("should treat a trailing result implementing error as the error", func() {
+		Expect(container.Provide(func() (*Database, *connError) { return nil, &connError{host: "db:5432"} })).To(Succeed())
+		Expect(container.Provide(func() (*Cache, *connError) { return &Cache{}, nil })).To(Succeed())
+
+		var db *Database
+		var connErr *connError
+		Expect(errors.As(container.Resolve(&db), &connErr)).To(BeTrue())
+		Expect(connErr.host).To(Equal("db:5432"))
+
+		var cache *Cache
+		Expect(container.Resolve(&cache)).To(Succeed())
+		Expect(cache).ToNot(BeNil())
+	})
+})
+
+type connError struct{ host string }
+
+func (e *connError) Error() string { return "cannot connect to " + e.host }
+
+// nextLine returns the location of the line after the call, where the constructor under test is provided
+func nextLine() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", file, line+1)
+}
@@ -0,0 +1,74 @@
+package compoapp_test
+
+import (
+	"reflect"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+var _ = Describe("Generic API", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should get typed instances", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		auth, err := compoapp.Get[*AuthService](container)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(auth.db).To(BeIdenticalTo(compoapp.MustGet[*Database](container)))
+	})
+
+	It("should get named and scoped instances", func() {
+		Expect(container.Provide(NewDatabase, compoapp.Name("primary"))).To(Succeed())
+		Expect(container.Provide(NewCache, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+
+		db, err := compoapp.Get[*Database](container, compoapp.Named("primary"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(db).ToNot(BeNil())
+
+		scope := container.NewScope()
+		Expect(compoapp.MustGet[*Cache](scope)).To(BeIdenticalTo(compoapp.MustGet[*Cache](scope)))
+	})
+
+	It("should return resolution errors", func() {
+		_, err := compoapp.Get[*Database](container)
+		Expect(err).To(MatchError(ContainSubstring("no instance found for type *compoapp_test.Database")))
+		Expect(func() { compoapp.MustGet[*Database](container) }).To(Panic())
+	})
+
+	It("should check constructors provide the type", func() {
+		Expect(compoapp.ProvideFunc[*Database](container, NewDatabase)).To(Succeed())
+		Expect(compoapp.ProvideFunc[*Cache](container, NewAuthService)).To(
+			MatchError("constructor func(*compoapp_test.Database) *compoapp_test.AuthService does not provide *compoapp_test.Cache"))
+		Expect(compoapp.ProvideFunc[*Cache](container, "not a function")).To(MatchError("constructor must be a function"))
+	})
+
+	It("should bind implementations provided for an interface", func() {
+		compoapp.MustProvideFunc[Storage](container, NewFileStorage)
+		Expect(container.Provide(func() *MeteredStorage { return &MeteredStorage{} })).To(Succeed())
+
+		storage, err := compoapp.Get[Storage](container)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(storage).To(BeAssignableToTypeOf(&FileStorage{}))
+	})
+
+	It("should resolve by reflect.Type", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+
+		db, err := container.ResolveType(reflect.TypeFor[*Database]())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(db).To(BeAssignableToTypeOf(&Database{}))
+
+		_, err = container.NewScope().ResolveType(reflect.TypeFor[*Cache]())
+		Expect(err).To(MatchError(ContainSubstring("no instance found")))
+		_, err = container.ResolveType(nil)
+		Expect(err).To(MatchError("cannot resolve untyped nil"))
+	})
+})
@@ -0,0 +1,128 @@
+package compoapp_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type ServiceParams struct {
+	compoapp.In
+
+	DB       *Database
+	Replica  *Database `name:"replica"`
+	Cache    *Cache    `optional:"true"`
+	Storages []Storage `group:"storages"`
+	Storage  Storage
+}
+
+type ParamService struct {
+	params ServiceParams
+}
+
+func NewParamService(p ServiceParams) *ParamService {
+	return &ParamService{params: p}
+}
+
+var _ = Describe("In parameter structs", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should inject every field of the parameter struct", func() {
+		newReplica := func() *Database { return &Database{Host: "replica:5432"} }
+		newGrouped := func() *FileStorage { return &FileStorage{path: "/grouped"} }
+
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newReplica, compoapp.Name("replica"))).To(Succeed())
+		Expect(container.Provide(newGrouped, compoapp.Group("storages"))).To(Succeed())
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(NewParamService)).To(Succeed())
+
+		var service *ParamService
+		Expect(container.Resolve(&service)).To(Succeed())
+		Expect(service.params.DB.Host).To(Equal("localhost:5432"))
+		Expect(service.params.Replica.Host).To(Equal("replica:5432"))
+		Expect(service.params.Cache).To(BeNil())
+		Expect(service.params.Storages).To(HaveLen(1))
+		Expect(service.params.Storage.(*FileStorage).path).To(Equal("/tmp"))
+	})
+
+	It("should inject optional fields when they are provided", func() {
+		type Params struct {
+			compoapp.In
+			Cache *Cache `optional:"true"`
+		}
+		type Service struct{ cache *Cache }
+		newService := func(p Params) *Service { return &Service{cache: p.Cache} }
+
+		Expect(container.Provide(NewCache)).To(Succeed())
+		Expect(container.Provide(newService)).To(Succeed())
+
+		var service *Service
+		Expect(container.Resolve(&service)).To(Succeed())
+		Expect(service.cache).ToNot(BeNil())
+	})
+
+	It("should mix parameter structs with plain parameters", func() {
+		type Params struct {
+			compoapp.In
+			Cache *Cache
+		}
+		type Service struct {
+			db    *Database
+			cache *Cache
+		}
+		newService := func(db *Database, p Params) *Service { return &Service{db: db, cache: p.Cache} }
+
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(NewCache)).To(Succeed())
+		Expect(container.Provide(newService)).To(Succeed())
+
+		var service *Service
+		Expect(container.Resolve(&service)).To(Succeed())
+		Expect(service.db).ToNot(BeNil())
+		Expect(service.cache).ToNot(BeNil())
+	})
+
+	It("should report missing field dependencies", func() {
+		Expect(container.Provide(NewParamService)).To(Succeed())
+
+		var service *ParamService
+		Expect(container.Resolve(&service)).To(MatchError(ContainSubstring("compoapp_test.Storage")))
+	})
+
+	It("should detect cycles through fields", func() {
+		type Params struct {
+			compoapp.In
+			Service *ParamService
+		}
+		newDatabase := func(Params) *Database { return &Database{} }
+
+		Expect(container.Provide(newDatabase)).To(Succeed())
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(NewParamService, compoapp.ParamTags())).To(Succeed())
+		Expect(container.Provide(func() *Database { return &Database{} }, compoapp.Name("replica"))).To(Succeed())
+
+		var service *ParamService
+		Expect(container.Resolve(&service)).To(MatchError(ContainSubstring("circular dependency detected")))
+	})
+
+	It("should reject unexported fields", func() {
+		type Params struct {
+			compoapp.In
+			db *Database
+		}
+		newService := func(Params) *ParamService { return &ParamService{} }
+
+		Expect(container.Provide(newService)).To(MatchError(ContainSubstring("field db of compoapp_test.Params must be exported")))
+	})
+
+	It("should reject param tags on parameter structs", func() {
+		Expect(container.Provide(NewParamService, compoapp.ParamTags(`name:"x"`))).To(
+			MatchError(ContainSubstring("parameter 0 is an In struct, tag its fields instead")))
+	})
+})
@@ -0,0 +1,146 @@
+package compoapp_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type migrator struct {
+	steps *[]string
+}
+
+func (m *migrator) Init(context.Context) error {
+	*m.steps = append(*m.steps, "init")
+	return nil
+}
+
+var _ = Describe("Invoke", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should call the function with injected arguments", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+
+		var (
+			gotDB      *Database
+			gotStorage Storage
+		)
+		Expect(container.Invoke(func(db *Database, storage Storage) {
+			gotDB, gotStorage = db, storage
+		})).To(Succeed())
+		Expect(gotDB).ToNot(BeNil())
+		Expect(gotStorage).To(BeAssignableToTypeOf(&FileStorage{}))
+	})
+
+	It("should build only the needed dependencies", func() {
+		cacheCalls := 0
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(func() *Cache {
+			cacheCalls++
+			return &Cache{}
+		})).To(Succeed())
+
+		container.MustInvoke(func(*Database) {})
+		Expect(cacheCalls).To(Equal(0))
+	})
+
+	It("should return the error of the function", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Invoke(func(*Database) error { return errors.New("migration failed") })).To(
+			MatchError("migration failed"))
+	})
+
+	It("should reject invalid functions", func() {
+		Expect(container.Invoke(42)).To(MatchError("invoked value must be a function"))
+		Expect(container.Invoke(func() *Database { return nil })).To(
+			MatchError("invoked function must return nothing or an error"))
+	})
+
+	It("should fail on missing dependencies", func() {
+		Expect(container.Invoke(func(*Database) {})).To(
+			MatchError(ContainSubstring("missing constructor for dependency type: *compoapp_test.Database")))
+	})
+
+	It("should run setup steps before Init", func() {
+		var steps []string
+		Expect(container.Provide(func() *migrator { return &migrator{steps: &steps} })).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var m *migrator
+		runner := container.ResolveLifecycle(&m).Invoke(func(m *migrator) {
+			steps = append(steps, "setup")
+		}).Invoke(cancel)
+		Expect(runner.Execute(ctx)).To(Succeed())
+		Expect(steps).To(Equal([]string{"setup", "init"}))
+	})
+
+	It("should launch components built only for setup steps", func() {
+		var steps []string
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(func() *migrator { return &migrator{steps: &steps} })).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var db *Database
+		runner := container.ResolveLifecycle(&db).Invoke(func(*migrator) {
+			steps = append(steps, "setup")
+		}).Invoke(cancel)
+		Expect(runner.Execute(ctx)).To(Succeed())
+		Expect(steps).To(Equal([]string{"setup", "init"}))
+	})
+
+	It("should pass the context of Execute to resolution and setup steps", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var db *Database
+		Expect(container.ResolveLifecycle(&db).Execute(ctx)).To(MatchError(context.Canceled))
+
+		ctx, cancel = context.WithCancel(context.WithValue(context.Background(), requestIDKey{}, "setup"))
+		var got any
+		runner := container.ResolveLifecycle(&db).Invoke(func(ctx context.Context) {
+			got = ctx.Value(requestIDKey{})
+		}, cancel, func(*Database) {})
+		Expect(runner.Execute(ctx)).To(MatchError(context.Canceled))
+		Expect(got).To(Equal("setup"))
+	})
+
+	It("should let the function build Lazy and Provider dependencies", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(func() *Session { return &Session{} }, compoapp.WithLifetime(compoapp.Transient))).To(Succeed())
+
+		invoked := func(lazy compoapp.Lazy[*Database], sessions compoapp.Provider[*Session]) error {
+			if _, err := lazy.Get(); err != nil {
+				return err
+			}
+			_, err := sessions()
+			return err
+		}
+
+		// a deadlock fails the spec instead of hanging the suite
+		done := make(chan error, 3)
+		go func() {
+			done <- container.Invoke(invoked)
+
+			scope := container.NewScope()
+			defer scope.Close()
+			done <- scope.Invoke(invoked)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var db *Database
+			done <- container.ResolveLifecycle(&db).Invoke(invoked).Invoke(cancel).Execute(ctx)
+		}()
+		for range 3 {
+			Eventually(done).Should(Receive(BeNil()))
+		}
+	})
+})
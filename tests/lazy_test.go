@@ -0,0 +1,149 @@
+package compoapp_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type ExpensiveClient struct {
+	db *Database
+}
+
+type ReportService struct {
+	client compoapp.Lazy[*ExpensiveClient]
+}
+
+func NewReportService(client compoapp.Lazy[*ExpensiveClient]) *ReportService {
+	return &ReportService{client: client}
+}
+
+var _ = Describe("Lazy", func() {
+	var (
+		container *compoapp.Container
+		calls     int
+	)
+
+	newExpensiveClient := func(db *Database) *ExpensiveClient {
+		calls++
+		return &ExpensiveClient{db: db}
+	}
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+		calls = 0
+	})
+
+	It("should construct lazy dependencies on first Get only", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newExpensiveClient)).To(Succeed())
+		Expect(container.Provide(NewReportService)).To(Succeed())
+
+		var reporter *ReportService
+		Expect(container.Resolve(&reporter)).To(Succeed())
+		Expect(calls).To(Equal(0))
+
+		first, err := reporter.client.Get()
+		Expect(err).ToNot(HaveOccurred())
+		second := reporter.client.MustGet()
+		Expect(first).To(BeIdenticalTo(second))
+		Expect(first.db).ToNot(BeNil())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should construct the lazy dependency once for concurrent callers", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newExpensiveClient)).To(Succeed())
+		Expect(container.Provide(NewReportService)).To(Succeed())
+
+		var reporter *ReportService
+		Expect(container.Resolve(&reporter)).To(Succeed())
+
+		wg := sync.WaitGroup{}
+		for range 10 {
+			wg.Go(func() {
+				defer GinkgoRecover()
+				_, err := reporter.client.Get()
+				Expect(err).ToNot(HaveOccurred())
+			})
+		}
+		wg.Wait()
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should return construction errors with the dependency chain", func() {
+		Expect(container.Provide(NewErrorDatabase)).To(Succeed())
+		Expect(container.Provide(newExpensiveClient)).To(Succeed())
+		Expect(container.Provide(NewReportService)).To(Succeed())
+
+		var reporter *ReportService
+		Expect(container.Resolve(&reporter)).To(Succeed())
+
+		_, err := reporter.client.Get()
+		Expect(err).To(MatchError(ContainSubstring("lazy *compoapp_test.ExpensiveClient required by *compoapp_test.ReportService")))
+		Expect(err).To(MatchError(ContainSubstring("database connection failed")))
+
+		_, again := reporter.client.Get()
+		Expect(again).To(Equal(err))
+	})
+
+	It("should detect cycles through lazy dependencies", func() {
+		newClient := func(*ReportService) *ExpensiveClient { return &ExpensiveClient{} }
+
+		Expect(container.Provide(newClient)).To(Succeed())
+		Expect(container.Provide(NewReportService)).To(Succeed())
+
+		var reporter *ReportService
+		Expect(container.Resolve(&reporter)).To(MatchError(ContainSubstring("circular dependency detected")))
+	})
+
+	It("should allow cycles broken by a lazy dependency", func() {
+		newClient := func(reporter *ReportService) *ExpensiveClient {
+			calls++
+			return &ExpensiveClient{}
+		}
+
+		Expect(container.Provide(newClient)).To(Succeed())
+		Expect(container.Provide(NewReportService, compoapp.ParamTags(`cycle:"break"`))).To(Succeed())
+
+		var reporter *ReportService
+		Expect(container.Resolve(&reporter)).To(Succeed())
+		Expect(calls).To(Equal(0))
+		Expect(reporter.client.MustGet()).ToNot(BeNil())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should reject cycle tags on plain parameters", func() {
+		Expect(container.Provide(NewAuthService, compoapp.ParamTags(`cycle:"break"`))).To(
+			MatchError(ContainSubstring("parameter 0: only lazy dependencies can break a cycle")))
+	})
+
+	It("should draw lazy edges dashed", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newExpensiveClient)).To(Succeed())
+		Expect(container.Provide(NewReportService)).To(Succeed())
+
+		var reporter *ReportService
+		Expect(container.Resolve(&reporter)).To(Succeed())
+
+		path := filepath.Join(GinkgoT().TempDir(), "graph.dot")
+		Expect(container.Visualize(path)).To(Succeed())
+		dot, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(dot)).To(ContainSubstring(`"*compoapp_test.ReportService" -> "*compoapp_test.ExpensiveClient" [style=dashed];`))
+		Expect(string(dot)).To(ContainSubstring(`"*compoapp_test.ExpensiveClient" -> "*compoapp_test.Database";`))
+	})
+
+	It("should fail when Get is called on a zero Lazy", func() {
+		var lazy compoapp.Lazy[*Database]
+		_, err := lazy.Get()
+		Expect(err).To(MatchError(ContainSubstring("was not injected")))
+		Expect(errors.Unwrap(err)).To(BeNil())
+	})
+})
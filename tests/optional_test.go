@@ -0,0 +1,97 @@
+package compoapp_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type Tracer struct{}
+
+type TracedService struct {
+	tracer compoapp.Optional[*Tracer]
+	cache  *Cache
+}
+
+func NewTracedService(tracer compoapp.Optional[*Tracer], cache *Cache) *TracedService {
+	return &TracedService{tracer: tracer, cache: cache}
+}
+
+var _ = Describe("Optional dependencies", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should inject empty values when nothing provides the dependency", func() {
+		Expect(container.Provide(NewTracedService, compoapp.ParamTags(``, `optional:"true"`))).To(Succeed())
+
+		var service *TracedService
+		Expect(container.Resolve(&service)).To(Succeed())
+
+		tracer, ok := service.tracer.Get()
+		Expect(ok).To(BeFalse())
+		Expect(tracer).To(BeNil())
+		Expect(service.cache).To(BeNil())
+	})
+
+	It("should inject provided optional dependencies", func() {
+		Expect(container.Provide(func() *Tracer { return &Tracer{} })).To(Succeed())
+		Expect(container.Provide(NewCache)).To(Succeed())
+		Expect(container.Provide(NewTracedService, compoapp.ParamTags(``, `optional:"true"`))).To(Succeed())
+
+		var service *TracedService
+		Expect(container.Resolve(&service)).To(Succeed())
+
+		tracer, ok := service.tracer.Get()
+		Expect(ok).To(BeTrue())
+		Expect(tracer).ToNot(BeNil())
+		Expect(service.tracer.Value()).To(BeIdenticalTo(tracer))
+		Expect(service.cache).ToNot(BeNil())
+	})
+
+	It("should skip optional interfaces without implementations", func() {
+		type Service struct{ storage compoapp.Optional[Storage] }
+		newService := func(storage compoapp.Optional[Storage]) *Service { return &Service{storage: storage} }
+
+		Expect(container.Provide(newService)).To(Succeed())
+
+		var service *Service
+		Expect(container.Resolve(&service)).To(Succeed())
+		_, ok := service.storage.Get()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should resolve optional interfaces to implementations", func() {
+		type Service struct{ storage compoapp.Optional[Storage] }
+		newService := func(storage compoapp.Optional[Storage]) *Service { return &Service{storage: storage} }
+
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(newService)).To(Succeed())
+
+		var service *Service
+		Expect(container.Resolve(&service)).To(Succeed())
+		Expect(service.storage.Value()).To(BeAssignableToTypeOf(&FileStorage{}))
+	})
+
+	It("should draw optional edges dotted", func() {
+		Expect(container.Provide(NewCache)).To(Succeed())
+		Expect(container.Provide(NewTracedService)).To(Succeed())
+
+		var service *TracedService
+		Expect(container.Resolve(&service)).To(Succeed())
+
+		path := filepath.Join(GinkgoT().TempDir(), "graph.dot")
+		Expect(container.Visualize(path)).To(Succeed())
+		dot, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(dot)).To(ContainSubstring(`"*compoapp_test.Tracer" [style="rounded,dotted"];`))
+		Expect(string(dot)).To(ContainSubstring(`"*compoapp_test.TracedService" -> "*compoapp_test.Tracer" [style=dotted];`))
+		Expect(string(dot)).To(ContainSubstring(`"*compoapp_test.TracedService" -> "*compoapp_test.Cache";`))
+	})
+})
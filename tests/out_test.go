@@ -0,0 +1,128 @@
+package compoapp_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type Pool struct{}
+
+type PoolMetrics struct{ pool *Pool }
+
+type PoolResult struct {
+	compoapp.Out
+
+	Pool    *Pool
+	Metrics *PoolMetrics `name:"pool"`
+	Storage Storage      `group:"storages"`
+}
+
+type initCounter struct{ inits int }
+
+func (i *initCounter) Init(context.Context) error {
+	i.inits++
+	return nil
+}
+
+type Initializer interface {
+	Init(ctx context.Context) error
+}
+
+var _ = Describe("Multiple results", func() {
+	var (
+		container *compoapp.Container
+		calls     int
+	)
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+		calls = 0
+	})
+
+	It("should register every result of a multi-value constructor", func() {
+		newPool := func() (*Pool, *PoolMetrics, error) {
+			calls++
+			pool := &Pool{}
+			return pool, &PoolMetrics{pool: pool}, nil
+		}
+		type Consumer struct {
+			pool    *Pool
+			metrics *PoolMetrics
+		}
+		newConsumer := func(p *Pool, m *PoolMetrics) *Consumer { return &Consumer{pool: p, metrics: m} }
+
+		Expect(container.Provide(newPool)).To(Succeed())
+		Expect(container.Provide(newConsumer)).To(Succeed())
+
+		var consumer *Consumer
+		Expect(container.Resolve(&consumer)).To(Succeed())
+		Expect(consumer.metrics.pool).To(BeIdenticalTo(consumer.pool))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should register fields of Out result structs with their tags", func() {
+		newPool := func() PoolResult {
+			calls++
+			pool := &Pool{}
+			return PoolResult{Pool: pool, Metrics: &PoolMetrics{pool: pool}, Storage: &FileStorage{path: "/pool"}}
+		}
+		type Consumer struct {
+			pool     *Pool
+			metrics  *PoolMetrics
+			storages []Storage
+		}
+		newConsumer := func(p *Pool, m *PoolMetrics, s []Storage) *Consumer {
+			return &Consumer{pool: p, metrics: m, storages: s}
+		}
+
+		Expect(container.Provide(newPool)).To(Succeed())
+		Expect(container.Provide(newConsumer, compoapp.ParamTags(``, `name:"pool"`, `group:"storages"`))).To(Succeed())
+
+		var consumer *Consumer
+		Expect(container.Resolve(&consumer)).To(Succeed())
+		Expect(consumer.metrics.pool).To(BeIdenticalTo(consumer.pool))
+		Expect(consumer.storages).To(HaveLen(1))
+		Expect(calls).To(Equal(1))
+
+		var metrics *PoolMetrics
+		Expect(container.Resolve(&metrics, compoapp.Named("pool"))).To(Succeed())
+	})
+
+	It("should propagate the trailing error", func() {
+		newPool := func() (*Pool, *PoolMetrics, error) {
+			return nil, nil, errors.New("pool failed")
+		}
+
+		Expect(container.Provide(newPool)).To(Succeed())
+
+		var metrics *PoolMetrics
+		Expect(container.Resolve(&metrics)).To(MatchError(ContainSubstring("pool failed")))
+	})
+
+	It("should initialize an instance provided twice only once", func() {
+		counter := &initCounter{}
+		newCounter := func() (*initCounter, Initializer) { return counter, counter }
+
+		Expect(container.Provide(newCounter)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var initializer Initializer
+		Expect(container.ResolveLifecycle(&initializer).Invoke(cancel).Execute(ctx)).To(Succeed())
+		Expect(counter.inits).To(Equal(1))
+	})
+
+	It("should reject constructors providing the same key twice", func() {
+		newPools := func() (*Pool, *Pool) { return &Pool{}, &Pool{} }
+		Expect(container.Provide(newPools)).To(MatchError(ContainSubstring("provides *compoapp_test.Pool more than once")))
+	})
+
+	It("should reject results the container builds itself", func() {
+		newPool := func() (*Pool, compoapp.Lazy[*Pool]) { return &Pool{}, compoapp.Lazy[*Pool]{} }
+		Expect(container.Provide(newPool)).To(MatchError(ContainSubstring("is built by the container and cannot be provided")))
+	})
+})
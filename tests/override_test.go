@@ -0,0 +1,65 @@
+package compoapp_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+var _ = Describe("Duplicate providers", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should reject a second constructor for the same type with both call sites", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		err := container.Provide(NewSuccessfulDatabase)
+		Expect(err).To(MatchError(MatchRegexp(
+			`^\*compoapp_test.Database is already provided by compoapp_test_test\.NewDatabase provided at .*override_test\.go:\d+, ` +
+				`provided again at .*override_test\.go:\d+, use Override to replace it$`)))
+		Expect(container.Supply(&Database{})).To(MatchError(ContainSubstring("is already provided")))
+	})
+
+	It("should allow the same type under different names", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(NewDatabase, compoapp.Name("replica"))).To(Succeed())
+	})
+
+	It("should replace constructors with Override", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+		Expect(container.Provide(func() *Database { return &Database{Host: "mock"} }, compoapp.Override())).To(Succeed())
+
+		var auth *AuthService
+		Expect(container.Resolve(&auth)).To(Succeed())
+		Expect(auth.db.Host).To(Equal("mock"))
+	})
+
+	It("should keep other results of a partly overridden constructor", func() {
+		newPool := func() (*Pool, *PoolMetrics) {
+			pool := &Pool{}
+			return pool, &PoolMetrics{pool: pool}
+		}
+		mock := &PoolMetrics{}
+
+		Expect(container.Provide(newPool)).To(Succeed())
+		Expect(container.Provide(func() *PoolMetrics { return mock }, compoapp.Override())).To(Succeed())
+
+		var pool *Pool
+		var metrics *PoolMetrics
+		Expect(container.Resolve(&pool)).To(Succeed())
+		Expect(container.Resolve(&metrics)).To(Succeed())
+		Expect(metrics).To(BeIdenticalTo(mock))
+	})
+
+	It("should not override constructed types", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+
+		var db *Database
+		Expect(container.Resolve(&db)).To(Succeed())
+		Expect(container.Provide(NewDatabase, compoapp.Override())).To(MatchError(ContainSubstring("it is already constructed")))
+	})
+})
@@ -0,0 +1,108 @@
+package compoapp_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+var errPoolExhausted = errors.New("pool exhausted")
+
+func NewPanickingDatabase() *Database {
+	panic("no driver")
+}
+
+var _ = Describe("Panics", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should be returned as errors with the stack and the path", func() {
+		Expect(container.Provide(NewPanickingDatabase)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		var auth *AuthService
+		err := container.Resolve(&auth)
+
+		var panicErr *compoapp.PanicError
+		Expect(errors.As(err, &panicErr)).To(BeTrue())
+		Expect(panicErr.Constructor).To(Equal("compoapp_test_test.NewPanickingDatabase"))
+		Expect(panicErr.Location).To(MatchRegexp(`panic_test\.go:\d+$`))
+		Expect(panicErr.Path).To(Equal([]string{"*compoapp_test.AuthService", "*compoapp_test.Database"}))
+		Expect(panicErr.Value).To(Equal("no driver"))
+		Expect(string(panicErr.Stack)).To(ContainSubstring("NewPanickingDatabase"))
+		Expect(err).To(MatchError(ContainSubstring("panicked: no driver")))
+	})
+
+	It("should unwrap to the panic value when it is an error", func() {
+		Expect(container.Provide(func() *Database { panic(errPoolExhausted) })).To(Succeed())
+
+		var db *Database
+		Expect(container.Resolve(&db)).To(MatchError(errPoolExhausted))
+	})
+
+	It("should recover panics of decorators and invoked functions", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Decorate(func(*Database) *Database { panic("decorator") })).To(Succeed())
+
+		var panicErr *compoapp.PanicError
+		var db *Database
+		Expect(errors.As(container.Resolve(&db), &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal("decorator"))
+
+		Expect(errors.As(container.Invoke(func() { panic("invoke") }), &panicErr)).To(BeTrue())
+		Expect(panicErr.Value).To(Equal("invoke"))
+	})
+
+	It("should recover panics of concurrent constructors", func() {
+		Expect(container.Provide(NewPanickingDatabase)).To(Succeed())
+		Expect(container.Provide(NewCache)).To(Succeed())
+		Expect(container.Provide(NewUserService)).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		var users *UserService
+		var panicErr *compoapp.PanicError
+		Expect(errors.As(container.ResolveContext(ctx, &users, compoapp.Parallel(2)), &panicErr)).To(BeTrue())
+		Expect(string(panicErr.Stack)).To(ContainSubstring("NewPanickingDatabase"))
+	})
+
+	It("should crash when configured", func() {
+		container.CrashOnPanic()
+		Expect(container.Provide(NewPanickingDatabase)).To(Succeed())
+
+		var db *Database
+		Expect(func() { _ = container.Resolve(&db) }).To(PanicWith("no driver"))
+	})
+
+	It("should crash with the stack of the constructor under a cancellable context", func() {
+		if os.Getenv("COMPOAPP_CRASH") == "1" {
+			container.CrashOnPanic()
+			container.MustProvide(NewPanickingDatabase)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var db *Database
+			_ = container.ResolveContext(ctx, &db)
+			return
+		}
+
+		// the crash takes the process down, so the spec runs in a child process
+		cmd := exec.Command(os.Args[0], "-test.run=TestContainer", "-ginkgo.focus=should crash with the stack")
+		cmd.Env = append(os.Environ(), "COMPOAPP_CRASH=1")
+		output, err := cmd.CombinedOutput()
+		Expect(err).To(HaveOccurred())
+		Expect(string(output)).To(ContainSubstring("panic: no driver"))
+		Expect(string(output)).To(MatchRegexp(`goroutine \d+ \[running\]:\n\S+\.NewPanickingDatabase\(`))
+	})
+})
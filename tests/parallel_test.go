@@ -0,0 +1,184 @@
+package compoapp_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type initLog struct {
+	mu    *sync.Mutex
+	steps *[]string
+}
+
+func (l initLog) record(step string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.steps = append(*l.steps, step)
+}
+
+type Certificates struct{ initLog }
+
+func (c *Certificates) Init(context.Context) error {
+	c.record("certificates")
+	return nil
+}
+
+type ConnPool struct{ initLog }
+
+func (p *ConnPool) Init(context.Context) error {
+	p.record("pool")
+	return nil
+}
+
+type CacheWarmer struct{ initLog }
+
+func (w *CacheWarmer) Init(context.Context) error {
+	w.record("warmer")
+	return nil
+}
+
+type Gateway struct{ initLog }
+
+func (g *Gateway) Init(context.Context) error {
+	g.record("gateway")
+	return nil
+}
+
+var _ = Describe("Parallel construction", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should run independent constructors concurrently", func() {
+		started := make(chan struct{}, 2)
+		// each constructor waits until the other one has started
+		rendezvous := func() bool {
+			started <- struct{}{}
+			deadline := time.After(time.Second)
+			for {
+				select {
+				case <-deadline:
+					return false
+				default:
+				}
+				if len(started) == 2 {
+					return true
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}
+
+		var concurrent atomic.Bool
+		concurrent.Store(true)
+		Expect(container.Provide(func() *Certificates {
+			concurrent.Store(concurrent.Load() && rendezvous())
+			return &Certificates{}
+		})).To(Succeed())
+		Expect(container.Provide(func() *ConnPool {
+			concurrent.Store(concurrent.Load() && rendezvous())
+			return &ConnPool{}
+		})).To(Succeed())
+		Expect(container.Provide(func(*Certificates, *ConnPool) *Gateway { return &Gateway{} })).To(Succeed())
+
+		var gateway *Gateway
+		Expect(container.Resolve(&gateway, compoapp.Parallel(2))).To(Succeed())
+		Expect(concurrent.Load()).To(BeTrue())
+	})
+
+	It("should bound the number of workers", func() {
+		var running, peak atomic.Int32
+		slow := func() {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		}
+
+		Expect(container.Provide(func() *Certificates { slow(); return &Certificates{} })).To(Succeed())
+		Expect(container.Provide(func() *ConnPool { slow(); return &ConnPool{} })).To(Succeed())
+		Expect(container.Provide(func() *CacheWarmer { slow(); return &CacheWarmer{} })).To(Succeed())
+		Expect(container.Provide(func() *Database { slow(); return &Database{} })).To(Succeed())
+		Expect(container.Provide(func(*Certificates, *ConnPool, *CacheWarmer, *Database) *Gateway {
+			return &Gateway{}
+		})).To(Succeed())
+
+		var gateway *Gateway
+		Expect(container.Resolve(&gateway, compoapp.Parallel(2))).To(Succeed())
+		Expect(peak.Load()).To(BeNumerically("<=", 2))
+	})
+
+	It("should build dependencies before their dependents", func() {
+		var pool *ConnPool
+		Expect(container.Provide(func() *ConnPool {
+			time.Sleep(10 * time.Millisecond)
+			pool = &ConnPool{}
+			return pool
+		})).To(Succeed())
+		// the transient warmer is built while the gateway collects its arguments
+		Expect(container.Provide(func(p *ConnPool) *CacheWarmer {
+			Expect(p).To(BeIdenticalTo(pool))
+			return &CacheWarmer{}
+		}, compoapp.WithLifetime(compoapp.Transient))).To(Succeed())
+		Expect(container.Provide(func() *Certificates { return &Certificates{} })).To(Succeed())
+		Expect(container.Provide(func(*Certificates, *CacheWarmer) *Gateway { return &Gateway{} })).To(Succeed())
+
+		var gateway *Gateway
+		Expect(container.Resolve(&gateway, compoapp.Parallel(4))).To(Succeed())
+	})
+
+	It("should stop scheduling on the first error", func() {
+		gatewayCalls := 0
+		Expect(container.Provide(func() (*Certificates, error) { return nil, errors.New("certificate expired") })).To(Succeed())
+		Expect(container.Provide(func() *ConnPool { return &ConnPool{} })).To(Succeed())
+		Expect(container.Provide(func(*Certificates, *ConnPool) *Gateway {
+			gatewayCalls++
+			return &Gateway{}
+		})).To(Succeed())
+
+		var gateway *Gateway
+		err := container.Resolve(&gateway, compoapp.Parallel(2))
+		var ctorErr *compoapp.ConstructorError
+		Expect(errors.As(err, &ctorErr)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("certificate expired")))
+		Expect(gatewayCalls).To(Equal(0))
+	})
+
+	It("should keep the order of components", func() {
+		initOrder := func(opts ...compoapp.ResolveOption) []string {
+			var mu sync.Mutex
+			var steps []string
+			log := initLog{mu: &mu, steps: &steps}
+
+			c := compoapp.NewContainer()
+			c.MustProvide(func() *Certificates { return &Certificates{log} })
+			c.MustProvide(func() *ConnPool { return &ConnPool{log} })
+			c.MustProvide(func(*ConnPool) *CacheWarmer { return &CacheWarmer{log} })
+			c.MustProvide(func(*Certificates, *CacheWarmer) *Gateway { return &Gateway{log} })
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var gateway *Gateway
+			Expect(c.ResolveLifecycle(&gateway, opts...).Invoke(cancel).Execute(ctx)).To(Succeed())
+			return steps
+		}
+
+		sequential := initOrder()
+		for range 20 {
+			Expect(initOrder(compoapp.Parallel(4))).To(Equal(sequential))
+		}
+	})
+})
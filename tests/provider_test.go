@@ -0,0 +1,98 @@
+package compoapp_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type Session struct {
+	db *Database
+	id int
+}
+
+type JobRunner struct {
+	sessions compoapp.Provider[*Session]
+}
+
+func NewJobRunner(sessions compoapp.Provider[*Session]) *JobRunner {
+	return &JobRunner{sessions: sessions}
+}
+
+var _ = Describe("Provider", func() {
+	var (
+		container *compoapp.Container
+		calls     int
+	)
+
+	newSession := func(db *Database) *Session {
+		calls++
+		return &Session{db: db, id: calls}
+	}
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+		calls = 0
+	})
+
+	It("should build a new instance on every call", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newSession)).To(Succeed())
+		Expect(container.Provide(NewJobRunner)).To(Succeed())
+
+		var runner *JobRunner
+		Expect(container.Resolve(&runner)).To(Succeed())
+		Expect(calls).To(Equal(0))
+
+		first, err := runner.sessions()
+		Expect(err).ToNot(HaveOccurred())
+		second, err := runner.sessions()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(first).ToNot(BeIdenticalTo(second))
+		Expect(first.id).To(Equal(1))
+		Expect(second.id).To(Equal(2))
+		Expect(first.db).To(BeIdenticalTo(second.db))
+	})
+
+	It("should accept plain factory functions", func() {
+		type Worker struct {
+			sessions func() (*Session, error)
+		}
+		newWorker := func(sessions func() (*Session, error)) *Worker {
+			return &Worker{sessions: sessions}
+		}
+
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newSession)).To(Succeed())
+		Expect(container.Provide(newWorker)).To(Succeed())
+
+		var worker *Worker
+		Expect(container.Resolve(&worker)).To(Succeed())
+
+		session, err := worker.sessions()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(session.db).ToNot(BeNil())
+	})
+
+	It("should return constructor errors", func() {
+		Expect(container.Provide(NewErrorDatabase)).To(Succeed())
+		Expect(container.Provide(newSession)).To(Succeed())
+		Expect(container.Provide(NewJobRunner)).To(Succeed())
+
+		var runner *JobRunner
+		Expect(container.Resolve(&runner)).To(Succeed())
+
+		session, err := runner.sessions()
+		Expect(err).To(MatchError(ContainSubstring("database connection failed")))
+		Expect(session).To(BeNil())
+	})
+
+	It("should fail resolution when the provided type has no constructor", func() {
+		Expect(container.Provide(NewJobRunner)).To(Succeed())
+
+		var runner *JobRunner
+		Expect(container.Resolve(&runner)).To(MatchError(ContainSubstring("missing constructor for dependency type: *compoapp_test.Session")))
+	})
+})
@@ -0,0 +1,200 @@
+package compoapp_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type backgroundWorker struct {
+	inits *int
+}
+
+func (w *backgroundWorker) Init(context.Context) error {
+	*w.inits++
+	return nil
+}
+
+var _ = Describe("Reachable subgraph", func() {
+	var (
+		container  *compoapp.Container
+		cacheCalls int
+	)
+
+	newCache := func() *Cache {
+		cacheCalls++
+		return &Cache{}
+	}
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+		cacheCalls = 0
+	})
+
+	It("should build only types the target depends on", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newCache)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		var auth *AuthService
+		Expect(container.Resolve(&auth)).To(Succeed())
+		Expect(auth.db).ToNot(BeNil())
+		Expect(cacheCalls).To(Equal(0))
+	})
+
+	It("should build every type with Eager", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newCache)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		var auth *AuthService
+		Expect(container.Resolve(&auth, compoapp.Eager())).To(Succeed())
+		Expect(cacheCalls).To(Equal(1))
+	})
+
+	It("should not report construction errors of unrelated types", func() {
+		Expect(container.Provide(NewErrorDatabase)).To(Succeed())
+		Expect(container.Provide(NewCache)).To(Succeed())
+
+		var cache *Cache
+		Expect(container.Resolve(&cache)).To(Succeed())
+		Expect(container.Resolve(&cache, compoapp.Eager())).To(MatchError(ContainSubstring("database connection failed")))
+	})
+
+	It("should check only types the target reaches", func() {
+		type Missing struct{}
+		type Reports struct{}
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(func(*Missing) *Reports { return &Reports{} })).To(Succeed())
+		// Storage is ambiguous for the processor
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(func() *MeteredStorage { return &MeteredStorage{} })).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+
+		var db *Database
+		Expect(container.Resolve(&db)).To(Succeed())
+		Expect(container.Invoke(func(*Database) {})).To(Succeed())
+
+		var ambiguous *compoapp.AmbiguousBindingError
+		Expect(errors.As(container.Resolve(&db, compoapp.Eager()), &ambiguous)).To(BeTrue())
+		err := container.Validate()
+		Expect(errors.As(err, &ambiguous)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("missing constructor for dependency type: *compoapp_test.Missing")))
+	})
+
+	It("should run the lifecycle of reachable types only", func() {
+		inits := 0
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(func() *backgroundWorker { return &backgroundWorker{inits: &inits} })).To(Succeed())
+
+		// the last setup step stops the run once the components are started
+		ctx, cancel := context.WithCancel(context.Background())
+		var db *Database
+		Expect(container.ResolveLifecycle(&db).Invoke(cancel).Execute(ctx)).To(Succeed())
+		Expect(inits).To(Equal(0))
+		ctx, cancel = context.WithCancel(context.Background())
+		Expect(container.ResolveLifecycle(&db, compoapp.Eager()).Invoke(cancel).Execute(ctx)).To(Succeed())
+		Expect(inits).To(Equal(1))
+	})
+})
+
+var _ = Describe("Repeated Resolve", func() {
+	var (
+		container *compoapp.Container
+		dbCalls   int
+	)
+
+	newDatabase := func() *Database {
+		dbCalls++
+		return &Database{}
+	}
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+		dbCalls = 0
+	})
+
+	It("should reuse instances built by earlier calls", func() {
+		Expect(container.Provide(newDatabase)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		var auth1, auth2 *AuthService
+		Expect(container.Resolve(&auth1)).To(Succeed())
+		Expect(container.Resolve(&auth2)).To(Succeed())
+		Expect(auth1).To(BeIdenticalTo(auth2))
+		Expect(dbCalls).To(Equal(1))
+	})
+
+	It("should construct newly provided types incrementally", func() {
+		Expect(container.Provide(newDatabase)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		var auth *AuthService
+		Expect(container.Resolve(&auth)).To(Succeed())
+
+		Expect(container.Provide(NewCache)).To(Succeed())
+		Expect(container.Provide(NewUserService)).To(Succeed())
+
+		var users *UserService
+		Expect(container.Resolve(&users)).To(Succeed())
+		Expect(users.db).To(BeIdenticalTo(auth.db))
+		Expect(dbCalls).To(Equal(1))
+	})
+
+	It("should repeat interface resolution after new registrations", func() {
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(NewDataProcessor, compoapp.WithLifetime(compoapp.Transient))).To(Succeed())
+
+		var processor *DataProcessor
+		Expect(container.Resolve(&processor)).To(Succeed())
+
+		Expect(container.Decorate(func(s Storage) Storage {
+			return &MeteredStorage{Storage: s, metrics: &Metrics{}}
+		})).To(Succeed())
+		Expect(container.Resolve(&processor)).To(Succeed())
+		Expect(processor.storage).To(BeAssignableToTypeOf(&MeteredStorage{}))
+	})
+
+	It("should keep the implementations built singletons received", func() {
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+
+		var processor *DataProcessor
+		Expect(container.Resolve(&processor)).To(Succeed())
+
+		// a second Storage is not ambiguous for the processor built with the first one
+		Expect(container.Provide(func() *MeteredStorage { return &MeteredStorage{} })).To(Succeed())
+		var metered *MeteredStorage
+		Expect(container.Resolve(&metered)).To(Succeed())
+
+		var again *DataProcessor
+		Expect(container.Resolve(&again)).To(Succeed())
+		Expect(again).To(BeIdenticalTo(processor))
+		Expect(again.storage).To(BeAssignableToTypeOf(&FileStorage{}))
+	})
+
+	It("should refuse to decorate constructed instances", func() {
+		Expect(container.Provide(newDatabase)).To(Succeed())
+
+		var db *Database
+		Expect(container.Resolve(&db)).To(Succeed())
+		Expect(container.Decorate(func(db *Database) *Database { return db })).To(
+			MatchError("cannot decorate *compoapp_test.Database: it is already constructed"))
+	})
+
+	It("should not launch components twice", func() {
+		inits := 0
+		Expect(container.Provide(func() *backgroundWorker { return &backgroundWorker{inits: &inits} })).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var worker *backgroundWorker
+		Expect(container.ResolveLifecycle(&worker).Invoke(cancel).Execute(ctx)).To(Succeed())
+		ctx, cancel = context.WithCancel(context.Background())
+		Expect(container.ResolveLifecycle(&worker).Invoke(cancel).Execute(ctx)).To(Succeed())
+		Expect(inits).To(Equal(1))
+	})
+})
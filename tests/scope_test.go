@@ -0,0 +1,149 @@
+package compoapp_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type UnitOfWork struct {
+	db     *Database
+	closed *[]string
+	name   string
+}
+
+func (u *UnitOfWork) Close() error {
+	*u.closed = append(*u.closed, u.name)
+	return nil
+}
+
+type RequestHandler struct {
+	uow *UnitOfWork
+}
+
+func NewRequestHandler(uow *UnitOfWork) *RequestHandler {
+	return &RequestHandler{uow: uow}
+}
+
+var _ = Describe("Scopes", func() {
+	var (
+		container *compoapp.Container
+		closed    []string
+	)
+
+	newUnitOfWork := func(db *Database) *UnitOfWork {
+		return &UnitOfWork{db: db, closed: &closed, name: "uow"}
+	}
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+		closed = nil
+	})
+
+	It("should share scoped instances within a scope and singletons across scopes", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newUnitOfWork, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+		Expect(container.Provide(NewRequestHandler, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+
+		first := container.NewScope()
+		second := container.NewScope()
+
+		var handler1, handler2 *RequestHandler
+		var uow1 *UnitOfWork
+		Expect(first.Resolve(&handler1)).To(Succeed())
+		Expect(first.Resolve(&uow1)).To(Succeed())
+		Expect(second.Resolve(&handler2)).To(Succeed())
+
+		Expect(handler1.uow).To(BeIdenticalTo(uow1))
+		Expect(handler1.uow).ToNot(BeIdenticalTo(handler2.uow))
+		Expect(handler1.uow.db).To(BeIdenticalTo(handler2.uow.db))
+	})
+
+	It("should build transient instances for every injection", func() {
+		type Pair struct{ a, b *Cache }
+		newPair := func(a, b *Cache) *Pair { return &Pair{a: a, b: b} }
+
+		Expect(container.Provide(NewCache, compoapp.WithLifetime(compoapp.Transient))).To(Succeed())
+		Expect(container.Provide(newPair)).To(Succeed())
+
+		var pair *Pair
+		Expect(container.Resolve(&pair)).To(Succeed())
+		Expect(pair.a).ToNot(BeIdenticalTo(pair.b))
+
+		var cache1, cache2 *Cache
+		Expect(container.Resolve(&cache1)).To(Succeed())
+		Expect(container.Resolve(&cache2)).To(Succeed())
+		Expect(cache1).ToNot(BeIdenticalTo(cache2))
+	})
+
+	It("should refuse to resolve scoped types from the root container", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newUnitOfWork, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+
+		var uow *UnitOfWork
+		Expect(container.Resolve(&uow)).To(MatchError(ContainSubstring("must be resolved from a scope")))
+	})
+
+	It("should reject singletons depending on scoped types", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newUnitOfWork, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+		Expect(container.Provide(NewRequestHandler)).To(Succeed())
+
+		var handler *RequestHandler
+		Expect(container.NewScope().Resolve(&handler)).To(MatchError(ContainSubstring("captive dependency")))
+	})
+
+	It("should reject singletons capturing scoped types through transient ones", func() {
+		type App struct{}
+		newApp := func(*RequestHandler) *App { return &App{} }
+
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newUnitOfWork, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+		Expect(container.Provide(NewRequestHandler, compoapp.WithLifetime(compoapp.Transient))).To(Succeed())
+		Expect(container.Provide(newApp)).To(Succeed())
+
+		var app *App
+		Expect(container.NewScope().Resolve(&app)).To(MatchError(ContainSubstring("captive dependency")))
+	})
+
+	It("should close scoped instances in reverse construction order", func() {
+		type Session struct{ UnitOfWork }
+		newFirst := func(db *Database) *UnitOfWork {
+			return &UnitOfWork{db: db, closed: &closed, name: "first"}
+		}
+		newSecond := func(uow *UnitOfWork) *Session {
+			return &Session{UnitOfWork{closed: &closed, name: "second"}}
+		}
+
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+		Expect(container.Provide(newFirst, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+		Expect(container.Provide(newSecond, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+
+		scope := container.NewScope()
+		var session *Session
+		Expect(scope.Resolve(&session)).To(Succeed())
+		Expect(scope.Close()).To(Succeed())
+		Expect(closed).To(Equal([]string{"second", "first"}))
+
+		Expect(scope.Resolve(&session)).To(MatchError("scope is closed"))
+	})
+
+	It("should return close errors", func() {
+		newFailing := func() *failingCloser { return &failingCloser{} }
+		Expect(container.Provide(newFailing, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+
+		scope := container.NewScope()
+		var fc *failingCloser
+		Expect(scope.Resolve(&fc)).To(Succeed())
+		Expect(scope.Close()).To(MatchError(ContainSubstring("close failed")))
+	})
+})
+
+type failingCloser struct{}
+
+func (f *failingCloser) Close() error {
+	return errors.New("close failed")
+}
@@ -0,0 +1,70 @@
+package compoapp_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+var _ = Describe("Supply", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should inject supplied values", func() {
+		config := &Config{Port: 9090}
+		Expect(container.Supply(config, &Database{Host: "supplied"})).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		var auth *AuthService
+		Expect(container.Resolve(&auth)).To(Succeed())
+		Expect(auth.db.Host).To(Equal("supplied"))
+
+		var resolved *Config
+		Expect(container.Resolve(&resolved)).To(Succeed())
+		Expect(resolved).To(BeIdenticalTo(config))
+	})
+
+	It("should use supplied values for interface resolution", func() {
+		storage := &FileStorage{path: "/supplied"}
+		container.MustSupply(storage)
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+
+		var processor *DataProcessor
+		Expect(container.Resolve(&processor)).To(Succeed())
+		Expect(processor.storage).To(BeIdenticalTo(storage))
+	})
+
+	It("should show supplied values as leaf nodes", func() {
+		Expect(container.Supply(&Database{})).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		var auth *AuthService
+		Expect(container.Resolve(&auth)).To(Succeed())
+
+		path := filepath.Join(GinkgoT().TempDir(), "graph.dot")
+		Expect(container.Visualize(path)).To(Succeed())
+		dot, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(dot)).To(ContainSubstring(`"*compoapp_test.AuthService" -> "*compoapp_test.Database";`))
+	})
+
+	It("should reject nil", func() {
+		Expect(container.Supply(nil)).To(MatchError("cannot supply untyped nil"))
+	})
+
+	It("should register no value when one is rejected", func() {
+		Expect(container.Supply(&Database{}, nil)).To(MatchError("cannot supply untyped nil"))
+		Expect(container.Supply(&Cache{}, &Cache{})).To(MatchError(ContainSubstring("*compoapp_test.Cache is supplied more than once")))
+
+		var db *Database
+		Expect(container.Resolve(&db)).To(MatchError(ContainSubstring("no instance found for type *compoapp_test.Database")))
+		Expect(container.Supply(&Database{}, &Cache{})).To(Succeed())
+	})
+})
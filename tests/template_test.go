@@ -0,0 +1,121 @@
+package compoapp_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type User struct{}
+
+type Order struct{}
+
+type Store[T any] interface {
+	Get() T
+}
+
+type Repository[T any] struct {
+	db *Database
+}
+
+func (r *Repository[T]) Get() T {
+	var zero T
+	return zero
+}
+
+func NewRepository[T any](db *Database) *Repository[T] {
+	return &Repository[T]{db: db}
+}
+
+type Cached[K comparable, V any] struct {
+	items map[K]V
+}
+
+func NewCached[K comparable, V any]() *Cached[K, V] {
+	return &Cached[K, V]{items: make(map[K]V)}
+}
+
+type OrderService struct {
+	users  Store[User]
+	orders Store[Order]
+}
+
+var _ = Describe("Templates", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+	})
+
+	It("should provide every instantiation", func() {
+		repositories := compoapp.NewTemplate().Instantiate(NewRepository[User], NewRepository[Order])
+		Expect(container.ProvideTemplate(repositories)).To(Succeed())
+		container.MustProvideTemplate(compoapp.NewTemplate().Instantiate(NewCached[string, User], NewCached[int, Order]))
+
+		users := compoapp.MustGet[*Repository[User]](container)
+		orders := compoapp.MustGet[*Repository[Order]](container)
+		Expect(users.db).To(BeIdenticalTo(orders.db))
+		Expect(compoapp.MustGet[*Cached[string, User]](container).items).ToNot(BeNil())
+		Expect(compoapp.MustGet[*Cached[int, Order]](container).items).ToNot(BeNil())
+	})
+
+	It("should match instantiated interfaces", func() {
+		container.MustProvideTemplate(compoapp.NewTemplate().Instantiate(NewRepository[User], NewRepository[Order]))
+		Expect(container.Provide(func(users Store[User], orders Store[Order]) *OrderService {
+			return &OrderService{users: users, orders: orders}
+		})).To(Succeed())
+
+		service := compoapp.MustGet[*OrderService](container)
+		Expect(service.users).To(BeAssignableToTypeOf(&Repository[User]{}))
+		Expect(service.orders).To(BeAssignableToTypeOf(&Repository[Order]{}))
+	})
+
+	It("should apply template options to every instantiation", func() {
+		repositories := compoapp.NewTemplate(compoapp.WithLifetime(compoapp.Transient))
+		repositories.Instantiate(NewRepository[User])
+		container.MustProvideTemplate(repositories)
+
+		Expect(compoapp.MustGet[*Repository[User]](container)).ToNot(BeIdenticalTo(compoapp.MustGet[*Repository[User]](container)))
+	})
+
+	It("should draw instantiations as separate nodes of a cluster", func() {
+		container.MustProvideTemplate(compoapp.NewTemplate().Instantiate(NewRepository[User], NewRepository[Order]))
+		compoapp.MustGet[*Repository[User]](container)
+
+		path := filepath.Join(GinkgoT().TempDir(), "graph.dot")
+		Expect(container.Visualize(path)).To(Succeed())
+		dot, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(dot)).To(MatchRegexp(`label="[\w.]+\.NewRepository\[\.\.\.\]";`))
+		// type arguments are named with their package path
+		Expect(string(dot)).To(MatchRegexp(`"\*compoapp_test\.Repository\[[\w.]+\.User\]" -> "\*compoapp_test\.Database";`))
+		Expect(string(dot)).To(MatchRegexp(`"\*compoapp_test\.Repository\[[\w.]+\.Order\]" -> "\*compoapp_test\.Database";`))
+	})
+
+	It("should reject constructors which are not instantiations of the same function", func() {
+		Expect(container.ProvideTemplate(compoapp.NewTemplate().Instantiate(NewCache))).To(
+			MatchError(ContainSubstring("is not an instantiation of a generic function")))
+		Expect(container.ProvideTemplate(compoapp.NewTemplate().Instantiate(NewRepository[User], NewCached[int, User]))).To(
+			MatchError(MatchRegexp(`NewCached\[\.\.\.\] instantiated at .*template_test\.go:\d+ is not an instantiation of .*NewRepository\[\.\.\.\]`)))
+		// nothing is registered, so the template may be provided once fixed
+		Expect(container.ProvideTemplate(compoapp.NewTemplate().Instantiate(NewRepository[User]))).To(Succeed())
+	})
+
+	It("should roll back earlier instantiations when one fails", func() {
+		Expect(container.Provide(func() *Repository[Order] { return &Repository[Order]{} })).To(Succeed())
+
+		repositories := compoapp.NewTemplate().Instantiate(NewRepository[User], NewRepository[Order])
+		Expect(container.ProvideTemplate(repositories)).To(MatchError(ContainSubstring("use Override to replace it")))
+		_, err := compoapp.Get[*Repository[User]](container)
+		Expect(err).To(MatchError(ContainSubstring("no instance found for type")))
+
+		repositories = compoapp.NewTemplate(compoapp.Override()).Instantiate(NewRepository[User], NewRepository[Order])
+		Expect(container.ProvideTemplate(repositories)).To(Succeed())
+		Expect(compoapp.MustGet[*Repository[Order]](container).db).ToNot(BeNil())
+	})
+})
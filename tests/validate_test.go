@@ -0,0 +1,78 @@
+package compoapp_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+var _ = Describe("Validate", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should succeed without calling constructors", func() {
+		calls := 0
+		Expect(container.Provide(func() *Database {
+			calls++
+			return &Database{}
+		})).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+
+		Expect(container.Validate()).To(Succeed())
+		Expect(calls).To(Equal(0))
+	})
+
+	It("should report every problem at once", func() {
+		type A struct{}
+		type B struct{}
+		Expect(container.Provide(func(*B) *A { return &A{} })).To(Succeed())
+		Expect(container.Provide(func(*A) *B { return &B{} })).To(Succeed())
+		Expect(container.Provide(NewUserService)).To(Succeed())
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(func() *MeteredStorage { return &MeteredStorage{} })).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+
+		err := container.Validate()
+
+		var missing *compoapp.MissingDependencyError
+		Expect(errors.As(err, &missing)).To(BeTrue())
+		var cycle *compoapp.CycleError
+		Expect(errors.As(err, &cycle)).To(BeTrue())
+		Expect(cycle.Cycles[0].Types).To(Equal([]string{"*compoapp_test.A", "*compoapp_test.B"}))
+		var ambiguous *compoapp.AmbiguousBindingError
+		Expect(errors.As(err, &ambiguous)).To(BeTrue())
+		Expect(ambiguous.Path).To(Equal([]string{"*compoapp_test.DataProcessor", "compoapp_test.Storage"}))
+
+		Expect(err).To(MatchError(ContainSubstring("*compoapp_test.Database, required by")))
+		Expect(err).To(MatchError(ContainSubstring("*compoapp_test.Cache, required by")))
+	})
+
+	It("should report captive dependencies", func() {
+		Expect(container.Provide(NewDatabase, compoapp.WithLifetime(compoapp.Scoped))).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+
+		Expect(container.Validate()).To(MatchError(ContainSubstring("captive dependency")))
+	})
+
+	It("should leave the container untouched", func() {
+		Expect(container.Provide(NewFileStorage)).To(Succeed())
+		Expect(container.Provide(NewDataProcessor)).To(Succeed())
+		Expect(container.Validate()).To(Succeed())
+
+		// interface resolution of Validate must not bind Storage to FileStorage
+		Expect(container.Provide(func() *MeteredStorage { return &MeteredStorage{} })).To(Succeed())
+		Expect(compoapp.Bind[Storage, *MeteredStorage](container)).To(Succeed())
+
+		var processor *DataProcessor
+		Expect(container.Resolve(&processor)).To(Succeed())
+		Expect(processor.storage).To(BeAssignableToTypeOf(&MeteredStorage{}))
+	})
+})
@@ -0,0 +1,102 @@
+package compoapp_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type Route struct {
+	Path string
+}
+
+type Timeout time.Duration
+
+type Handler func(context.Context) error
+
+type Greeter interface {
+	Greet() string
+}
+
+type English struct{}
+
+func (English) Greet() string { return "hello" }
+
+type Shouter struct{}
+
+func (*Shouter) Greet() string { return "HELLO" }
+
+var _ = Describe("Value types", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+	})
+
+	It("should provide values of any kind", func() {
+		Expect(container.Provide(func() Config { return Config{Port: 8080} })).To(Succeed())
+		Expect(container.Provide(func() []Route { return []Route{{Path: "/"}} })).To(Succeed())
+		Expect(container.Provide(func() map[string]Handler {
+			return map[string]Handler{"ping": func(context.Context) error { return nil }}
+		})).To(Succeed())
+		Expect(container.Supply(Timeout(time.Second))).To(Succeed())
+		Expect(container.Provide(func() func(context.Context) error {
+			return func(context.Context) error { return errors.New("stopped") }
+		})).To(Succeed())
+
+		Expect(container.Invoke(func(cfg Config, routes []Route, handlers map[string]Handler, timeout Timeout, stop func(context.Context) error) {
+			Expect(cfg.Port).To(Equal(8080))
+			Expect(routes).To(Equal([]Route{{Path: "/"}}))
+			Expect(handlers).To(HaveKey("ping"))
+			Expect(timeout).To(Equal(Timeout(time.Second)))
+			Expect(stop(context.Background())).To(MatchError("stopped"))
+		})).To(Succeed())
+	})
+
+	It("should match T and *T exactly and suggest the provided one", func() {
+		Expect(container.Provide(NewDatabase)).To(Succeed())
+
+		var db Database
+		Expect(container.Resolve(&db)).To(MatchError(
+			"no instance found for type compoapp_test.Database, *compoapp_test.Database is provided, request it instead"))
+
+		Expect(container.Provide(func() Config { return Config{Port: 8080} })).To(Succeed())
+		Expect(container.Provide(NewServer)).To(Succeed())
+		Expect(container.Provide(NewUserService)).To(Succeed())
+		Expect(container.Provide(NewAuthService)).To(Succeed())
+		Expect(container.Provide(NewCache)).To(Succeed())
+
+		var server *Server
+		err := container.Resolve(&server)
+		Expect(err).To(MatchError(ContainSubstring("missing constructor for dependency type: *compoapp_test.Config")))
+		Expect(err).To(MatchError(HaveSuffix(", compoapp_test.Config is provided, request it instead")))
+	})
+
+	It("should bind interfaces only to types whose own methods implement them", func() {
+		Expect(container.Provide(func() Shouter { return Shouter{} })).To(Succeed())
+		Expect(container.Provide(func() English { return English{} })).To(Succeed())
+
+		Expect(container.Invoke(func(greeter Greeter) {
+			Expect(greeter.Greet()).To(Equal("hello"))
+		})).To(Succeed())
+	})
+
+	It("should report ambiguous requests when T and *T both implement an interface", func() {
+		Expect(container.Provide(func() English { return English{} })).To(Succeed())
+		Expect(container.Provide(func() *English { return &English{} })).To(Succeed())
+
+		err := container.Invoke(func(Greeter) {})
+		var ambiguous *compoapp.AmbiguousBindingError
+		Expect(errors.As(err, &ambiguous)).To(BeTrue())
+		Expect(ambiguous.Implementations).To(ConsistOf("compoapp_test.English", "*compoapp_test.English"))
+	})
+
+	It("should reject providing errors", func() {
+		Expect(container.Provide(func() error { return nil })).To(MatchError(ContainSubstring("cannot provide error values")))
+	})
+})
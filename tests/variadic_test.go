@@ -0,0 +1,82 @@
+package compoapp_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/trofkm/compoapp"
+)
+
+type HTTPServer struct {
+	port int
+	tls  bool
+}
+
+type ServerOption func(*HTTPServer)
+
+func WithPort(port int) ServerOption {
+	return func(s *HTTPServer) { s.port = port }
+}
+
+func WithTLS() ServerOption {
+	return func(s *HTTPServer) { s.tls = true }
+}
+
+func NewHTTPServer(cfg *Config, opts ...ServerOption) *HTTPServer {
+	s := &HTTPServer{port: cfg.Port}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ = Describe("Variadic parameters", func() {
+	var container *compoapp.Container
+
+	BeforeEach(func() {
+		container = compoapp.NewContainer()
+		Expect(container.Supply(&Config{Port: 80})).To(Succeed())
+	})
+
+	It("should be empty when nothing is provided", func() {
+		Expect(container.Provide(NewHTTPServer)).To(Succeed())
+
+		server, err := compoapp.Get[*HTTPServer](container)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.port).To(Equal(80))
+	})
+
+	It("should collect members of every group in registration order", func() {
+		Expect(container.Provide(func() ServerOption { return WithPort(8080) }, compoapp.Group("ports"))).To(Succeed())
+		Expect(container.Provide(func() ServerOption { return WithTLS() }, compoapp.Group("security"))).To(Succeed())
+		Expect(container.Provide(func() ServerOption { return WithPort(8443) }, compoapp.Group("ports"))).To(Succeed())
+		Expect(container.Provide(NewHTTPServer)).To(Succeed())
+
+		server := compoapp.MustGet[*HTTPServer](container)
+		Expect(server.port).To(Equal(8443))
+		Expect(server.tls).To(BeTrue())
+	})
+
+	It("should take a single group when tagged", func() {
+		Expect(container.Provide(func() ServerOption { return WithPort(8080) }, compoapp.Group("ports"))).To(Succeed())
+		Expect(container.Provide(func() ServerOption { return WithTLS() }, compoapp.Group("security"))).To(Succeed())
+		Expect(container.Provide(NewHTTPServer, compoapp.ParamTags("", `group:"ports"`))).To(Succeed())
+
+		server := compoapp.MustGet[*HTTPServer](container)
+		Expect(server.port).To(Equal(8080))
+		Expect(server.tls).To(BeFalse())
+	})
+
+	It("should be injected into invoked functions", func() {
+		Expect(container.Provide(func() ServerOption { return WithTLS() }, compoapp.Group("security"))).To(Succeed())
+
+		Expect(container.Invoke(func(opts ...ServerOption) {
+			Expect(opts).To(HaveLen(1))
+		})).To(Succeed())
+	})
+
+	It("should reject names", func() {
+		Expect(container.Provide(NewHTTPServer, compoapp.ParamTags("", `name:"options"`))).To(
+			MatchError(ContainSubstring("variadic parameter cannot be named")))
+	})
+})
@@ -0,0 +1,71 @@
+package compoapp
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// Validate checks the registrations without calling any constructor: interface resolution,
+// missing dependencies, cycles and captive dependencies. Every problem found is returned joined.
+// The container is left untouched, so it may be validated again after more registrations.
+func (c *Container) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v := c.snapshot()
+	v.debugf("validating container")
+
+	_, err := v.resolveInterfaces(nil)
+	errs := []error{err}
+	v.rebuildGraph()
+	errs = append(errs, v.validateDependencies(nil))
+
+	// sorting stops at missing dependencies, so cycles are searched among all the types
+	nodes := make(map[key]bool, len(v.typesCtors))
+	for k := range v.typesCtors {
+		nodes[k] = true
+	}
+	if cycles := v.findCycles(nodes); len(cycles) > 0 {
+		errs = append(errs, &CycleError{Cycles: cycles, node: cycles[0].keys[0]})
+	} else {
+		errs = append(errs, v.validateLifetimes(nil))
+	}
+
+	err = errors.Join(errs...)
+	v.tracePaths(err, key{})
+	return err
+}
+
+// snapshot copies the registrations, so that interface resolution may run on the copy
+func (c *Container) snapshot() *Container {
+	v := NewContainer()
+	v.debug = c.debug
+	v.typeRegistry = slices.Clone(c.typeRegistry)
+	v.groups = maps.Clone(c.groups)
+	v.bindings = maps.Clone(c.bindings)
+
+	copies := make(map[*constructorInfo]*constructorInfo)
+	clone := func(ctor *constructorInfo) *constructorInfo {
+		if cp, ok := copies[ctor]; ok {
+			return cp
+		}
+		cp := *ctor
+		cp.signature.args = slices.Clone(ctor.signature.args)
+		copies[ctor] = &cp
+		return &cp
+	}
+
+	for _, ctor := range c.constructors {
+		v.constructors = append(v.constructors, clone(ctor))
+	}
+	for k, ctor := range c.typesCtors {
+		v.typesCtors[k] = clone(ctor)
+	}
+	for k, layers := range c.decorators {
+		for _, d := range layers {
+			v.decorators[k] = append(v.decorators[k], &decorator{constructorInfo: clone(d.constructorInfo), decorated: d.decorated})
+		}
+	}
+	return v
+}